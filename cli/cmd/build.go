@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/config"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/output"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/pipeline"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	buildRepoURL     string
+	buildMaxVersions int
+	buildWorkDir     string
+	buildOutputDir   string
+	buildFormats     []string
+	buildSkipDiff    bool
+	buildStrategy    string
+	buildWorkers     int
+	buildLocale      string
+	buildTranslation string
+	buildRules       string
+	buildFailOnEx    bool
+	buildSiteURL     string
+	buildSkipLint    bool
+	buildLintRules   string
+	buildFailOnLint  string
+	buildAudiences   []string
+)
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "ダウンロード、差分計算、ドキュメント生成をまとめて実行します。",
+	Long: `設定ファイルの sources に従って仕様ファイルをダウンロードし、バージョン間の差分計算とドキュメントの生成を1回の実行で行います。
+仕様ファイルは一度だけ解析され、差分計算と生成で共有されます。
+--repo-url を指定すると設定ファイルの sources の代わりにそのリポジトリを使います。
+取得元の指定がない場合はダウンロードを行わず、--work-dir にある仕様ファイルを使います。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cmd.Flags().Changed("repo-url") {
+			cfg.Sources = []config.Source{{Repo: buildRepoURL}}
+		}
+		if cmd.Flags().Changed("max-versions") {
+			for i := range cfg.Sources {
+				cfg.Sources[i].MaxVersions = buildMaxVersions
+			}
+		}
+		cfg.Layout.WorkDir = flagOrString(cmd, "work-dir", buildWorkDir, cfg.Layout.WorkDir)
+		cfg.Layout.OutputDir = flagOrString(cmd, "output", buildOutputDir, cfg.Layout.OutputDir)
+		cfg.Output.Formats = flagOrStrings(cmd, "format", buildFormats, cfg.Output.Formats)
+		cfg.Output.FailOnInvalidExamples = flagOrBool(cmd, "fail-on-invalid-examples", buildFailOnEx, cfg.Output.FailOnInvalidExamples)
+		cfg.Output.SiteURL = flagOrString(cmd, "site-url", buildSiteURL, cfg.Output.SiteURL)
+		cfg.Diff.Enabled = !flagOrBool(cmd, "skip-diff", buildSkipDiff, !cfg.Diff.Enabled)
+		cfg.Diff.Strategy = flagOrString(cmd, "strategy", buildStrategy, cfg.Diff.Strategy)
+		cfg.Diff.Workers = flagOrInt(cmd, "workers", buildWorkers, cfg.Diff.Workers)
+		cfg.Diff.Locale = flagOrString(cmd, "locale", buildLocale, cfg.Diff.Locale)
+		cfg.Diff.Translations = flagOrString(cmd, "translations", buildTranslation, cfg.Diff.Translations)
+		cfg.Diff.Rules = flagOrString(cmd, "rules", buildRules, cfg.Diff.Rules)
+		cfg.Lint.Enabled = !flagOrBool(cmd, "skip-lint", buildSkipLint, !cfg.Lint.Enabled)
+		cfg.Lint.Rules = flagOrString(cmd, "lint-rules", buildLintRules, cfg.Lint.Rules)
+		cfg.Lint.FailOn = flagOrString(cmd, "fail-on-lint", buildFailOnLint, cfg.Lint.FailOn)
+		cfg.Output.Audiences = flagOrStrings(cmd, "audience", buildAudiences, cfg.Output.Audiences)
+
+		result, err := pipeline.Run(cfg)
+		if result != nil {
+			printStages(result.Stages)
+			printLintFailures(result.LintFailures)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		printUntranslated(cfg.Diff.Locale, result.Untranslated)
+		printVersionMismatches(result.VersionMismatches)
+
+		fmt.Println("\n✅ ビルドが完了しました。")
+		fmt.Printf("出力先: %s\n", cfg.Layout.OutputDir)
+	},
+}
+
+// printStages はステージごとの所要時間と結果を表形式で出力します。
+func printStages(stages []pipeline.StageResult) {
+	fmt.Println()
+	var total time.Duration
+	for _, stage := range stages {
+		if stage.Skipped {
+			fmt.Printf("%-10s %10s  スキップ\n", stage.Name, "-")
+			continue
+		}
+		total += stage.Duration
+		fmt.Printf("%-10s %10s  %s\n", stage.Name, stage.Duration.Round(time.Millisecond), stage.Summary)
+	}
+	fmt.Printf("%-10s %10s\n", "total", total.Round(time.Millisecond))
+}
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+
+	buildCmd.Flags().StringVarP(&buildRepoURL, "repo-url", "u", "", "OpenAPI仕様ファイルを含むGitリポジトリのURL (設定ファイルの sources を上書き)")
+	buildCmd.Flags().IntVarP(&buildMaxVersions, "max-versions", "n", 5, "APIごとに収集する最大のバージョン数")
+	buildCmd.Flags().StringVarP(&buildWorkDir, "work-dir", "w", "downloaded_apis", "ダウンロード先かつ差分計算・生成の入力ディレクトリ")
+	buildCmd.Flags().StringVarP(&buildOutputDir, "output", "o", "dist", "生成されたサイトの出力先ディレクトリ")
+	buildCmd.Flags().StringSliceVarP(&buildFormats, "format", "f", []string{output.FormatJSON}, "出力フォーマット ("+strings.Join(output.Formats(), ", ")+")")
+	buildCmd.Flags().BoolVar(&buildSkipDiff, "skip-diff", false, "差分計算を行わず既存の diff.json を使う")
+	buildCmd.Flags().StringVarP(&buildStrategy, "strategy", "s", string(diff.StrategyConsecutive), "差分を計算するバージョンの組み合わせ (consecutive, latest, previous-major, full)")
+	buildCmd.Flags().StringVar(&buildLocale, "locale", diff.LocaleJa, "差分メッセージの言語 (ja, en など)")
+	buildCmd.Flags().StringVar(&buildTranslation, "translations", "", "組み込みの翻訳を上書き・追加する翻訳ファイル")
+	buildCmd.Flags().StringVar(&buildRules, "rules", "", "既知の変更を承認済みにする無視ルールと重要度の上書きのファイル")
+	buildCmd.Flags().IntVar(&buildWorkers, "workers", 0, "同時に実行する差分計算の数 (0 なら CPU 数)")
+	buildCmd.Flags().BoolVar(&buildFailOnEx, "fail-on-invalid-examples", false, "最新バージョンにスキーマに適合しないExampleがある場合に失敗させる")
+	buildCmd.Flags().StringVar(&buildSiteURL, "site-url", "", "公開するサイトのURL (feed のリンクに使用)")
+	buildCmd.Flags().StringSliceVar(&buildAudiences, "audience", nil, "対象者ごとに x-internal / x-audience で絞り込んだサイトを <output>/<対象者> に出力する (複数指定可。internal には全てを出力)")
+	buildCmd.Flags().BoolVar(&buildSkipLint, "skip-lint", false, "仕様書のスタイルの検査を行わない")
+	buildCmd.Flags().StringVar(&buildLintRules, "lint-rules", "", "lint のルールのレベルの上書きとルールの設定のファイル")
+	buildCmd.Flags().StringVar(&buildFailOnLint, "fail-on-lint", "", "最新バージョンにこのレベル以上の lint の問題があれば失敗させる (ERR, WARN, INFO)")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/changelog"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	changelogInput        string
+	changelogFrom         string
+	changelogTo           string
+	changelogFormat       string
+	changelogOutput       string
+	changelogTemplate     string
+	changelogLocale       string
+	changelogTranslations string
+	changelogRules        string
+)
+
+// changelogCmd represents the changelog command
+var changelogCmd = &cobra.Command{
+	Use:   "changelog <api>",
+	Short: "API のバージョンの範囲についてリリースノートを生成します。",
+	Long: `入力ディレクトリ (-i) の API のバージョンを並べ、--from から --to までの連続するバージョンの変更を
+互換性を損なう変更・非推奨・追加・変更・ドキュメントの修正に分けたリリースノートを出力します。
+
+--from のバージョン自体の変更は含みません。--to を省略すると最新のバージョン、--from を省略すると --to の1つ前のバージョンになります。
+diff.json に差分がないバージョンの組はその場で比較します。
+
+markdown と html では --template で組み込みのテンプレートの代わりに Go のテンプレートを指定できます。
+テンプレートには .API、.From、.To と、新しい順の .Releases (各要素は .Version、.Previous、.Date、.Groups) が渡されます。`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := changelog.ValidateFormat(changelogFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		locale := flagOrString(cmd, "locale", changelogLocale, cfg.Diff.Locale)
+		localizer, err := diff.NewLocalizer(locale, flagOrString(cmd, "translations", changelogTranslations, cfg.Diff.Translations))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		rules, err := loadRules(flagOrString(cmd, "rules", changelogRules, cfg.Diff.Rules))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		docs, err := parseInputDirs(inputDirs(cmd, "input", changelogInput))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		cl, err := changelog.Build(docs, args[0], changelog.Options{
+			From:      changelogFrom,
+			To:        changelogTo,
+			Localizer: localizer,
+			Rules:     rules,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeChangelog(cl); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		printUntranslated(locale, localizer.Untranslated())
+	},
+}
+
+// writeChangelog はリリースノートを --output (未指定なら標準出力) に書き出します。
+func writeChangelog(cl *changelog.Changelog) error {
+	var w io.Writer = os.Stdout
+	if changelogOutput != "" {
+		f, err := os.Create(changelogOutput)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return changelog.Write(w, cl, changelogFormat, changelogTemplate)
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+
+	changelogCmd.Flags().StringVarP(&changelogInput, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	changelogCmd.Flags().StringVar(&changelogFrom, "from", "", "範囲の起点のバージョン (このバージョンの変更は含まない。省略時は --to の1つ前)")
+	changelogCmd.Flags().StringVar(&changelogTo, "to", "", "範囲の終点のバージョン (省略時は最新のバージョン)")
+	changelogCmd.Flags().StringVarP(&changelogFormat, "format", "f", changelog.FormatMarkdown, "出力フォーマット ("+strings.Join(changelog.Formats, ", ")+")")
+	changelogCmd.Flags().StringVarP(&changelogOutput, "output", "o", "", "出力先ファイル (未指定なら標準出力)")
+	changelogCmd.Flags().StringVar(&changelogTemplate, "template", "", "markdown と html で使う Go のテンプレートファイル")
+	changelogCmd.Flags().StringVar(&changelogLocale, "locale", diff.LocaleJa, "差分メッセージの言語 (ja, en など)")
+	changelogCmd.Flags().StringVar(&changelogTranslations, "translations", "", "組み込みの翻訳を上書き・追加する翻訳ファイル")
+	changelogCmd.Flags().StringVar(&changelogRules, "rules", "", "既知の変更を承認済みにする無視ルールと重要度の上書きのファイル")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/check"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"os"
+	"strings"
+)
+
+var (
+	checkFailOn       string
+	checkGitHub       bool
+	checkJUnit        string
+	checkLocale       string
+	checkTranslations string
+	checkRules        string
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check <base> <revision>",
+	Short: "2つの仕様を比較し、互換性を損なう変更があれば失敗します。",
+	Long: `base から revision への変更を検出し、--fail-on で指定したレベル以上の変更があれば終了コード 1 で終了します。
+CI でマージ前に互換性を損なう変更を検出する用途を想定しています。
+
+base と revision には次のいずれかを指定できます。
+  - 仕様ファイルのパス
+  - 仕様ファイルを1つだけ含むディレクトリ
+  - http(s) の URL
+  - git:<ref>:<path> (例: git:origin/main:api/openapi.yaml)
+
+終了コード:
+  0  --fail-on 以上の変更なし
+  1  --fail-on 以上の変更あり
+  2  仕様の読み込みなどに失敗`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		failOn, err := downloader.ParseLevel(checkFailOn)
+		if err != nil {
+			exitCheckError(err)
+		}
+		localizer, err := diff.NewLocalizer(
+			flagOrString(cmd, "locale", checkLocale, cfg.Diff.Locale),
+			flagOrString(cmd, "translations", checkTranslations, cfg.Diff.Translations),
+		)
+		if err != nil {
+			exitCheckError(err)
+		}
+
+		rules, err := loadRules(flagOrString(cmd, "rules", checkRules, cfg.Diff.Rules))
+		if err != nil {
+			exitCheckError(err)
+		}
+
+		result, err := check.Run(args[0], args[1], failOn, localizer, rules)
+		if err != nil {
+			exitCheckError(err)
+		}
+
+		result.WriteSummary(os.Stdout)
+		if checkGitHub {
+			check.WriteGitHubAnnotations(os.Stdout, result)
+		}
+		if checkJUnit != "" {
+			if err := writeJUnitFile(checkJUnit, result); err != nil {
+				exitCheckError(err)
+			}
+		}
+		printUntranslated(localizer.Locale(), localizer.Untranslated())
+
+		if result.Failed() {
+			os.Exit(1)
+		}
+	},
+}
+
+// writeJUnitFile は結果を JUnit XML ファイルに書き出します。
+func writeJUnitFile(path string, result *check.Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("JUnit XML ファイルの作成に失敗しました: %w", err)
+	}
+	defer f.Close()
+	return check.WriteJUnit(f, result)
+}
+
+// exitCheckError はエラーを表示し、変更の検出と区別できるよう終了コード 2 で終了します。
+func exitCheckError(err error) {
+	fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+	os.Exit(2)
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().StringVar(&checkFailOn, "fail-on", "ERR", "このレベル以上の変更があれば失敗する ("+strings.Join(downloader.Levels, ", ")+")")
+	checkCmd.Flags().BoolVar(&checkGitHub, "github-annotations", os.Getenv("GITHUB_ACTIONS") == "true", "GitHub Actions のアノテーションを出力する (GitHub Actions 上では既定で有効)")
+	checkCmd.Flags().StringVar(&checkJUnit, "junit", "", "JUnit XML 形式の結果を書き出すファイル")
+	checkCmd.Flags().StringVar(&checkLocale, "locale", diff.LocaleJa, "差分メッセージの言語 (ja, en など)")
+	checkCmd.Flags().StringVar(&checkTranslations, "translations", "", "組み込みの翻訳を上書き・追加する翻訳ファイル")
+	checkCmd.Flags().StringVar(&checkRules, "rules", "", "既知の変更を承認済みにする無視ルールと重要度の上書きのファイル")
+}
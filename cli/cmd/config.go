@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+)
+
+// flagOrString はフラグが指定されていればフラグの値を、そうでなければ設定ファイルの値を返します。
+func flagOrString(cmd *cobra.Command, name string, flagValue string, configValue string) string {
+	if cmd.Flags().Changed(name) || configValue == "" {
+		return flagValue
+	}
+	return configValue
+}
+
+// flagOrStrings はフラグが指定されていればフラグの値を、そうでなければ設定ファイルの値を返します。
+func flagOrStrings(cmd *cobra.Command, name string, flagValue []string, configValue []string) []string {
+	if cmd.Flags().Changed(name) || len(configValue) == 0 {
+		return flagValue
+	}
+	return configValue
+}
+
+// flagOrInt はフラグが指定されていればフラグの値を、そうでなければ設定ファイルの値を返します。
+func flagOrInt(cmd *cobra.Command, name string, flagValue int, configValue int) int {
+	if cmd.Flags().Changed(name) {
+		return flagValue
+	}
+	return configValue
+}
+
+// flagOrBool はフラグが指定されていればフラグの値を、そうでなければ設定ファイルの値を返します。
+func flagOrBool(cmd *cobra.Command, name string, flagValue bool, configValue bool) bool {
+	if cmd.Flags().Changed(name) {
+		return flagValue
+	}
+	return configValue
+}
+
+// inputDirs はフラグが指定されていればフラグのディレクトリだけを、そうでなければ設定ファイルの
+// layout.workDir と sources[].dir の入力ディレクトリを返します。
+func inputDirs(cmd *cobra.Command, name string, flagValue string) []string {
+	if cmd.Flags().Changed(name) {
+		return []string{flagValue}
+	}
+	return cfg.InputDirs()
+}
+
+// parseInputDirs は入力ディレクトリの仕様ファイルを解析し、設定ファイルの apis に一致する API のドキュメントを返します。
+func parseInputDirs(dirs []string) ([]*parser.APIDocument, error) {
+	var docs []*parser.APIDocument
+	for _, dir := range dirs {
+		parsed, err := parser.ParseAPIDocs(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range parsed {
+			if cfg.APIs.IncludesAPI(doc.APIName) {
+				docs = append(docs, doc)
+			}
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/deprecation"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	deprecationsInput         string
+	deprecationsAPIs          []string
+	deprecationsFormat        string
+	deprecationsOutput        string
+	deprecationsAsOf          string
+	deprecationsFailOnOverdue bool
+)
+
+// deprecationsCmd represents the deprecations command
+var deprecationsCmd = &cobra.Command{
+	Use:   "deprecations",
+	Short: "非推奨のオペレーションと廃止予定日の一覧を出力します。",
+	Long: `入力ディレクトリ (-i) の API の全バージョンから、deprecated: true、x-sunset、レスポンスの Sunset ヘッダー、
+x-stability-level を読み取り、オペレーションごとに非推奨になったバージョン、廃止予定日、削除済みかどうかを出力します。
+
+--fail-on-overdue を指定すると、廃止予定日を過ぎても最新のバージョンに残っているオペレーションがある場合に終了コード 1 で終了します。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := deprecation.ValidateFormat(deprecationsFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		now := time.Now()
+		if deprecationsAsOf != "" {
+			t, err := time.Parse("2006-01-02", deprecationsAsOf)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "エラー: --as-of は YYYY-MM-DD 形式で指定してください: %s\n", deprecationsAsOf)
+				os.Exit(1)
+			}
+			now = t
+		}
+
+		docs, err := parseInputDirs(inputDirs(cmd, "input", deprecationsInput))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		siteData, err := generator.Aggregate(docs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		report := deprecation.NewReport(siteData, deprecationsAPIs, now)
+		if err := writeDeprecations(report); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		if deprecationsFailOnOverdue && report.Overdue() > 0 {
+			fmt.Fprintf(os.Stderr, "エラー: 廃止予定日を過ぎても残っているオペレーションが%d件あります\n", report.Overdue())
+			os.Exit(1)
+		}
+	},
+}
+
+// writeDeprecations はレポートを --output (未指定なら標準出力) に書き出します。
+func writeDeprecations(report *deprecation.Report) error {
+	var w io.Writer = os.Stdout
+	if deprecationsOutput != "" {
+		f, err := os.Create(deprecationsOutput)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return deprecation.Write(w, report, deprecationsFormat)
+}
+
+func init() {
+	rootCmd.AddCommand(deprecationsCmd)
+
+	deprecationsCmd.Flags().StringVarP(&deprecationsInput, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	deprecationsCmd.Flags().StringSliceVar(&deprecationsAPIs, "api", nil, "対象の API 名 (複数指定可。省略時は全て)")
+	deprecationsCmd.Flags().StringVarP(&deprecationsFormat, "format", "f", deprecation.FormatText, "出力フォーマット ("+strings.Join(deprecation.Formats, ", ")+")")
+	deprecationsCmd.Flags().StringVarP(&deprecationsOutput, "output", "o", "", "出力先ファイル (未指定なら標準出力)")
+	deprecationsCmd.Flags().StringVar(&deprecationsAsOf, "as-of", "", "廃止予定日を過ぎたかを判定する日付 (YYYY-MM-DD、省略時は現在)")
+	deprecationsCmd.Flags().BoolVar(&deprecationsFailOnOverdue, "fail-on-overdue", false, "廃止予定日を過ぎても最新のバージョンに残っているオペレーションがある場合に失敗させる")
+}
@@ -1,44 +1,156 @@
 package cmd
 
 import (
+	"errors"
+	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/check"
 	diff2 "github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"os"
+	"strings"
 )
 
 //var inputDir string
 
+var (
+	diffStrategy     string
+	diffWorkers      int
+	diffLocale       string
+	diffTranslations string
+	diffVerbose      bool
+	diffFormat       string
+	diffOutput       string
+	diffRules        string
+)
+
 var diffCmd = &cobra.Command{
-	Use:   "diff",
-	Short: "Gen Diff",
-	Long:  "Generate Diff",
+	Use:   "diff [base revision]",
+	Short: "バージョン間の差分を計算します。",
+	Long: `引数を指定しない場合は、入力ディレクトリ (-i) の API ごとにバージョン間の差分を計算し、各バージョンの diff.json に書き出します。
+
+base と revision を指定した場合は、その2つの仕様の差分を --format で指定した形式で出力します。
+base と revision には次のいずれかを指定できます。
+  - 仕様ファイルのパス
+  - 仕様ファイルを1つだけ含むディレクトリ
+  - http(s) の URL
+  - git:<ref>:<path> (例: git:main:api/openapi.yaml)`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("引数は指定しないか、base と revision の2つを指定してください")
+		}
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		locale := flagOrString(cmd, "locale", diffLocale, cfg.Diff.Locale)
+		localizer, err := diff2.NewLocalizer(locale, flagOrString(cmd, "translations", diffTranslations, cfg.Diff.Translations))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
 
-		diff2.GetAllDiff(inputDir)
-
-		//loader := openapi3.NewLoader()
-		//loader.IsExternalRefsAllowed = true
-		//
-		//spec1, err := load.NewSpecInfo(loader, load.NewSource(inputFile))
-		//if err != nil {
-		//	log.Fatalf("failed to load old spec: %v", err)
-		//}
-		//
-		//spec2, err := load.NewSpecInfo(loader, load.NewSource(inputFile2))
-		//if err != nil {
-		//	log.Fatalf("failed to load new spec: %v", err)
-		//}
-		//
-		//diff, err := diff2.GetDiff(spec1, spec2)
-		//if err != nil {
-		//	log.Fatalf("failed to get diff: %v", err)
-		//}
-		//os.WriteFile("test.json", diff, os.FileMode(0644))
+		rules, err := loadRules(flagOrString(cmd, "rules", diffRules, cfg.Diff.Rules))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 2 {
+			if err := runDirectDiff(args[0], args[1], localizer, rules); err != nil {
+				fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+				os.Exit(1)
+			}
+			printUntranslated(locale, localizer.Untranslated())
+			return
+		}
+
+		dirs := inputDirs(cmd, "input", inputDir)
+		strategy, err := diff2.ParseStrategy(flagOrString(cmd, "strategy", diffStrategy, cfg.Diff.Strategy))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		options := diff2.Options{
+			Strategy:  strategy,
+			Workers:   flagOrInt(cmd, "workers", diffWorkers, cfg.Diff.Workers),
+			Localizer: localizer,
+			Rules:     rules,
+			Logf:      diffLogf(diffVerbose),
+		}
+		// 一部の API で失敗しても残りの差分は書き出し、最後にまとめて報告する
+		var errs []error
+		for _, dir := range dirs {
+			errs = append(errs, diff2.GetAllDiff(dir, options))
+		}
+		printUntranslated(locale, localizer.Untranslated())
+		if err := errors.Join(errs...); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
 	},
 }
 
+// runDirectDiff は2つの仕様の差分を --format の形式で --output (未指定なら標準出力) に書き出します。
+func runDirectDiff(base string, revision string, localizer *diff2.Localizer, rules *diff2.Rules) error {
+	if err := check.ValidateFormat(diffFormat); err != nil {
+		return err
+	}
+	result, err := check.Run(base, revision, downloader.LevelErr, localizer, rules)
+	if err != nil {
+		return err
+	}
+
+	if diffOutput == "" {
+		return check.WriteReport(os.Stdout, result, diffFormat)
+	}
+	f, err := os.Create(diffOutput)
+	if err != nil {
+		return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+	}
+	defer f.Close()
+	return check.WriteReport(f, result, diffFormat)
+}
+
 func init() {
 	rootCmd.AddCommand(diffCmd)
 
-	diffCmd.Flags().StringVarP(&inputDir, "input", "i", "", "Input File")
-	generateCmd.MarkFlagRequired("input")
+	diffCmd.Flags().StringVarP(&inputDir, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	diffCmd.Flags().StringVarP(&diffStrategy, "strategy", "s", string(diff2.StrategyConsecutive), "差分を計算するバージョンの組み合わせ (consecutive, latest, previous-major, full)")
+	diffCmd.Flags().StringVar(&diffLocale, "locale", diff2.LocaleJa, "差分メッセージの言語 (ja, en など)")
+	diffCmd.Flags().StringVar(&diffTranslations, "translations", "", "組み込みの翻訳を上書き・追加する翻訳ファイル")
+	diffCmd.Flags().StringVar(&diffRules, "rules", "", "既知の変更を承認済みにする無視ルールと重要度の上書きのファイル")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", check.FormatText, "base と revision を指定した場合の出力フォーマット ("+strings.Join(check.Formats, ", ")+")")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "base と revision を指定した場合の出力先ファイル (未指定なら標準出力)")
+	diffCmd.Flags().BoolVarP(&diffVerbose, "verbose", "v", false, "バージョンの組ごとの変更数を表示する")
+	diffCmd.Flags().IntVar(&diffWorkers, "workers", 0, "同時に実行する差分計算の数 (0 なら CPU 数)")
+}
+
+// printUntranslated は翻訳が見つからず英語で出力した差分メッセージのキーを標準エラー出力に表示します。
+func printUntranslated(locale string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠️ ロケール %s の翻訳が見つからないメッセージが%d件あります (英語で出力しました):\n", locale, len(keys))
+	for _, key := range keys {
+		fmt.Fprintf(os.Stderr, "  - %s\n", key)
+	}
+}
+
+// diffLogf は verbose の場合に進捗を標準エラー出力に表示する関数を返します。
+func diffLogf(verbose bool) func(format string, args ...interface{}) {
+	if !verbose {
+		return nil
+	}
+	return func(format string, args ...interface{}) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
+// loadRules はルールファイルを読み込みます。path が空の場合は nil を返します。
+func loadRules(path string) (*diff2.Rules, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return diff2.LoadRules(path)
 }
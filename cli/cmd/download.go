@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"os"
 )
 
 var (
@@ -19,9 +20,30 @@ var downloadCmd = &cobra.Command{
 	Long: `指定されたGitリポジトリのコミット履歴を遡り、OpenAPI仕様ファイルを検索します。
 見つかったファイルは、API名ごとに最新のバージョンから指定された件数分だけ 'outputDir/api/apiName/version/' の形式で保存されます。`,
 	Run: func(cmd *cobra.Command, args []string) {
+		outputDirDownload = flagOrString(cmd, "output", outputDirDownload, cfg.Layout.WorkDir)
+		if !cmd.Flags().Changed("repo-url") {
+			for _, source := range cfg.Sources {
+				if source.Repo == "" {
+					continue
+				}
+				repoURL = source.Repo
+				if !cmd.Flags().Changed("max-versions") {
+					maxVersions = source.MaxVersionsOrDefault()
+				}
+				break
+			}
+		}
+		if repoURL == "" {
+			fmt.Fprintln(os.Stderr, "エラー: --repo-url か設定ファイルの sources でリポジトリを指定してください")
+			os.Exit(1)
+		}
+
 		fmt.Printf("Gitリポジトリのクローンを開始します: %s\n", repoURL)
 
-		downloader.Download(repoURL, outputDirDownload, maxVersions)
+		if err := downloader.Download(repoURL, outputDirDownload, maxVersions); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
 
 		fmt.Println("\n✅ OpenAPIファイルのダウンロードと整理が完了しました。")
 		fmt.Printf("出力先: %s\n", outputDirDownload)
@@ -31,8 +53,7 @@ var downloadCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(downloadCmd)
 
-	downloadCmd.Flags().StringVarP(&repoURL, "repo-url", "u", "", "OpenAPI仕様ファイルを含むGitリポジトリのURL (必須)")
+	downloadCmd.Flags().StringVarP(&repoURL, "repo-url", "u", "", "OpenAPI仕様ファイルを含むGitリポジトリのURL (省略時は設定ファイルの sources)")
 	downloadCmd.Flags().StringVarP(&outputDirDownload, "output", "o", "downloaded_apis", "ダウンロードしたファイルを保存するディレクトリ")
 	downloadCmd.Flags().IntVarP(&maxVersions, "max-versions", "n", 5, "APIごとに収集する最大のバージョン数")
-	downloadCmd.MarkFlagRequired("repo-url")
 }
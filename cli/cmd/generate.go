@@ -3,14 +3,28 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/audience"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/config"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/output"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/pipeline"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 )
 
 var inputDir string
 var outputDir string
+var failOnInvalidExamples bool
+var outputFormats []string
+var siteURL string
+var skipLint bool
+var lintRules string
+var failOnLint string
+var audiences []string
 
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
@@ -20,10 +34,29 @@ var generateCmd = &cobra.Command{
 解析結果を元にNext.jsサイトが参照する単一のJSONファイルを生成します。
 その後、静的サイトのビルドを行います。`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("ドキュメント生成を開始します (入力: %s)\n", inputDir)
+		dirs := inputDirs(cmd, "input", inputDir)
+		outputDir = flagOrString(cmd, "output", outputDir, cfg.Layout.OutputDir)
+		outputFormats = flagOrStrings(cmd, "format", outputFormats, cfg.Output.Formats)
+		failOnInvalidExamples = flagOrBool(cmd, "fail-on-invalid-examples", failOnInvalidExamples, cfg.Output.FailOnInvalidExamples)
+		siteURL = flagOrString(cmd, "site-url", siteURL, cfg.Output.SiteURL)
+		skipLint = flagOrBool(cmd, "skip-lint", skipLint, !cfg.Lint.Enabled)
+		lintRules = flagOrString(cmd, "lint-rules", lintRules, cfg.Lint.Rules)
+		failOnLint = flagOrString(cmd, "fail-on-lint", failOnLint, cfg.Lint.FailOn)
+		audiences = flagOrStrings(cmd, "audience", audiences, cfg.Output.Audiences)
+
+		if err := output.Validate(outputFormats); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		if err := config.ValidateAudiences(audiences); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("ドキュメント生成を開始します (入力: %s)\n", strings.Join(dirs, ", "))
 
 		// 1. OpenAPIファイルを解析
-		docs, err := parser.ParseAPIDocs(inputDir)
+		docs, err := parseInputDirs(dirs)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
 			os.Exit(1)
@@ -35,24 +68,106 @@ var generateCmd = &cobra.Command{
 		}
 		fmt.Printf("%d個のAPIドキュメントを正常に解析しました。\n", len(docs))
 
-		// 2. 解析したデータを元にJSONファイルを生成
-		err = generator.GenerateJSON(docs, outputDir)
+		// 2. 仕様書のスタイルを検査
+		if !skipLint {
+			if err := lintDocs(docs, lintRules, failOnLint); err != nil {
+				fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// 3. 解析したデータを集約
+		siteData, err := generator.Aggregate(docs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		// 4. 最新バージョンのExampleの検証結果を確認
+		invalidExamples := generator.InvalidExamplesInLatest(siteData)
+		for _, invalid := range invalidExamples {
+			fmt.Fprintf(os.Stderr, "⚠ スキーマに適合しないExample: %s\n", invalid)
+		}
+		if failOnInvalidExamples && len(invalidExamples) > 0 {
+			fmt.Fprintf(os.Stderr, "エラー: 最新バージョンに不正なExampleが%d件あります\n", len(invalidExamples))
+			os.Exit(1)
+		}
+
+		printVersionMismatches(generator.VersionMismatches(siteData))
+		for _, overdue := range generator.OverdueDeprecations(siteData, time.Now()) {
+			fmt.Fprintf(os.Stderr, "⚠ 廃止予定日を過ぎたオペレーション: %s\n", overdue)
+		}
+
+		// 5. 指定されたフォーマットで出力 (対象者が指定されていれば対象者ごとに絞り込んで出力)
+		var written []output.Written
+		if len(audiences) == 0 {
+			written, err = output.Write(outputFormats, docs, siteData, outputDir, output.Options{SiteURL: siteURL})
+		} else {
+			var filter audience.Options
+			if filter, err = pipeline.AudienceOptions(cfg, !skipLint, lintRules); err == nil {
+				written, err = output.WriteAudiences(outputFormats, docs, audiences, outputDir, output.Options{SiteURL: siteURL}, filter)
+			}
+		}
+		for _, w := range written {
+			fmt.Printf("✔ %s を出力しました: %s\n", w.Format, w.Path)
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("\n✅ ドキュメントの元となるJSONファイルの生成が完了しました。")
-		fmt.Printf("出力先: %s\n", filepath.Join(outputDir, "data", "api-data.json"))
+		fmt.Println("\n✅ ドキュメントの生成が完了しました。")
+		fmt.Printf("出力先: %s\n", outputDir)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 
-	generateCmd.Flags().StringVarP(&inputDir, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (必須)")
-	generateCmd.MarkFlagRequired("input")
+	generateCmd.Flags().StringVarP(&inputDir, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
 
 	generateCmd.Flags().StringVarP(&outputDir, "output", "o", "dist", "生成されたサイトの出力先ディレクトリ")
+	generateCmd.Flags().StringSliceVarP(&outputFormats, "format", "f", []string{output.FormatJSON}, "出力フォーマット ("+strings.Join(output.Formats(), ", ")+")")
+	generateCmd.Flags().BoolVar(&failOnInvalidExamples, "fail-on-invalid-examples", false, "最新バージョンにスキーマに適合しないExampleがある場合に失敗させる")
+	generateCmd.Flags().StringVar(&siteURL, "site-url", "", "公開するサイトのURL (feed のリンクに使用)")
+	generateCmd.Flags().BoolVar(&skipLint, "skip-lint", false, "仕様書のスタイルの検査を行わない")
+	generateCmd.Flags().StringVar(&lintRules, "lint-rules", "", "lint のルールのレベルの上書きとルールの設定のファイル")
+	generateCmd.Flags().StringSliceVar(&audiences, "audience", nil, "対象者ごとに x-internal / x-audience で絞り込んだサイトを <output>/<対象者> に出力する (複数指定可。internal には全てを出力)")
+	generateCmd.Flags().StringVar(&failOnLint, "fail-on-lint", "", "最新バージョンにこのレベル以上の lint の問題があれば失敗させる (ERR, WARN, INFO)")
+}
+
+// lintDocs はドキュメントを検査して結果を記録し、最新バージョンに failOn 以上の問題があればエラーを返します。
+func lintDocs(docs []*parser.APIDocument, rulesFile string, failOn string) error {
+	ruleset, err := lint.LoadRuleset(rulesFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("lint: %d件の問題を検出しました。\n", generator.LintDocs(docs, ruleset))
+	if failOn == "" {
+		return nil
+	}
+	level, err := downloader.ParseLevel(failOn)
+	if err != nil {
+		return err
+	}
+	failures := generator.LintFailuresInLatest(docs, level)
+	printLintFailures(failures)
+	if len(failures) > 0 {
+		return fmt.Errorf("最新バージョンに %s 以上の lint の問題が%d件あります", strings.ToUpper(failOn), len(failures))
+	}
+	return nil
+}
+
+// printLintFailures は最新バージョンで失敗とみなす lint の問題を標準エラー出力に表示します。
+func printLintFailures(failures []generator.LintFailure) {
+	for _, f := range failures {
+		fmt.Fprintf(os.Stderr, "✖ %s\n", f)
+	}
+}
 
+// printVersionMismatches は変更の内容に対してバージョンの上げ方が足りないバージョンを標準エラー出力に表示します。
+func printVersionMismatches(mismatches []generator.VersionMismatch) {
+	for _, m := range mismatches {
+		fmt.Fprintf(os.Stderr, "⚠ バージョンの上げ方が不足しています: %s\n", m)
+	}
 }
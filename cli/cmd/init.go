@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/config"
+	"os"
+)
+
+var forceInit bool
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [path]",
+	Short: "設定ファイルの雛形を作成します。",
+	Long:  "設定ファイルの雛形を作成します。path を省略した場合は ./" + config.DefaultFile + " に作成します。",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := config.DefaultFile
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		if err := config.WriteScaffold(path, forceInit); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ 設定ファイルを作成しました: %s\n", path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().BoolVar(&forceInit, "force", false, "既存のファイルを上書きする")
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"os"
+	"sort"
+	"strings"
+)
+
+var (
+	lintInput       string
+	lintAllVersions bool
+	lintRulesFile   string
+	lintFailOn      string
+	lintFormat      string
+	lintListRules   bool
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint [source...]",
+	Short: "仕様書がスタイルのルールに従っているかを検査します。",
+	Long: `operationId の有無と命名、タグ、パラメータの説明、エラーレスポンス、ページングの規約などの組み込みのルールで仕様書を検査します。
+
+引数を指定しない場合は、入力ディレクトリ (-i) の API ごとに最新のバージョン (--all-versions なら全てのバージョン) を検査します。
+引数には次のいずれかを指定できます。
+  - 仕様ファイルのパス
+  - 仕様ファイルを1つだけ含むディレクトリ
+  - http(s) の URL
+  - git:<ref>:<path> (例: git:main:api/openapi.yaml)
+
+ルールのレベルの上書きと無効化、ページングのパラメータは --lint-rules のファイルで設定できます。
+
+終了コード:
+  0  --fail-on 以上の問題なし
+  1  --fail-on 以上の問題あり
+  2  仕様の読み込みなどに失敗`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ruleset, err := lint.LoadRuleset(flagOrString(cmd, "lint-rules", lintRulesFile, cfg.Lint.Rules))
+		if err != nil {
+			exitCheckError(err)
+		}
+		if lintListRules {
+			for _, rule := range lint.Rules() {
+				level := downloader.Change{Level: ruleset.Level(rule.ID)}.LevelName()
+				if ruleset.Level(rule.ID) == lint.LevelOff {
+					level = "off"
+				}
+				fmt.Printf("%-36s %-4s  %s\n", rule.ID, level, rule.Description)
+			}
+			return
+		}
+		failOn, err := downloader.ParseLevel(flagOrString(cmd, "fail-on", lintFailOn, cfg.Lint.FailOn))
+		if err != nil {
+			exitCheckError(err)
+		}
+		if err := lint.ValidateFormat(lintFormat); err != nil {
+			exitCheckError(err)
+		}
+
+		var targets []lint.Target
+		if len(args) > 0 {
+			targets, err = lintSources(args, ruleset)
+		} else {
+			targets, err = lintInputDirs(inputDirs(cmd, "input", lintInput), ruleset)
+		}
+		if err != nil {
+			exitCheckError(err)
+		}
+
+		if err := lint.Write(os.Stdout, targets, lintFormat); err != nil {
+			exitCheckError(err)
+		}
+		for _, target := range targets {
+			if lint.Count(target.Problems, failOn) > 0 {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// lintSources は引数で指定した仕様を検査します。
+func lintSources(sources []string, ruleset *lint.Ruleset) ([]lint.Target, error) {
+	var targets []lint.Target
+	for _, source := range sources {
+		spec, err := diff.LoadSpec(source)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, lint.Target{Name: source, Problems: ruleset.Lint(spec.Spec)})
+	}
+	return targets, nil
+}
+
+// lintInputDirs は入力ディレクトリの API ごとに最新のバージョン (--all-versions なら全て) を検査します。
+func lintInputDirs(inputDirs []string, ruleset *lint.Ruleset) ([]lint.Target, error) {
+	docs, err := parseInputDirs(inputDirs)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("対象のOpenAPIファイルが見つかりませんでした: %s", strings.Join(inputDirs, ", "))
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		if docs[i].APIName != docs[j].APIName {
+			return docs[i].APIName < docs[j].APIName
+		}
+		return semver.Compare(docs[i].Version, docs[j].Version) < 0
+	})
+
+	var targets []lint.Target
+	for i, doc := range docs {
+		latest := i == len(docs)-1 || docs[i+1].APIName != doc.APIName
+		if !lintAllVersions && !latest {
+			continue
+		}
+		targets = append(targets, lint.Target{Name: doc.APIName + " " + doc.Version, Problems: ruleset.Lint(doc.Doc)})
+	}
+	return targets, nil
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringVarP(&lintInput, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	lintCmd.Flags().BoolVar(&lintAllVersions, "all-versions", false, "最新のバージョンだけでなく全てのバージョンを検査する")
+	lintCmd.Flags().StringVar(&lintRulesFile, "lint-rules", "", "ルールのレベルの上書きとルールの設定のファイル (省略時は設定ファイルの lint.rules)")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "ERR", "このレベル以上の問題があれば失敗する ("+strings.Join(downloader.Levels, ", ")+") (省略時は設定ファイルの lint.failOn)")
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", lint.FormatText, "出力フォーマット ("+strings.Join(lint.Formats, ", ")+")")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "組み込みのルールと現在のレベルを一覧にする")
+}
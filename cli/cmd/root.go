@@ -5,26 +5,40 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/config"
 )
 
+var cfgFile string
 
+// cfg はルートコマンドの実行前に読み込まれるプロジェクト設定です。
+var cfg *config.Config
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cli",
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+	Short: "OpenAPIの仕様ファイルからバージョン付きの静的ドキュメントを生成します。",
+	Long: `GitリポジトリのコミットからOpenAPIの仕様ファイルを収集し、バージョン間の差分を計算して、
+Next.jsサイト向けのJSONや静的HTML、Markdownなどのドキュメントを生成します。
+
+設定は --config で指定したファイル (省略時はカレントディレクトリの ` + config.DefaultFile + `)、
+OASDOC_ で始まる環境変数、コマンドラインフラグの順に上書きされます。`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if cmd == initCmd {
+			return nil
+		}
+		loaded, err := config.Load(cfgFile)
+		if err != nil {
+			// 設定の誤りはフラグの使い方の問題ではないため使い方の表示は省略する
+			cmd.SilenceUsage = true
+			return err
+		}
+		cfg = loaded
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -37,15 +51,5 @@ func Execute() {
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
-
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cli.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", fmt.Sprintf("設定ファイル (省略時は ./%s があれば使用)", config.DefaultFile))
 }
-
-
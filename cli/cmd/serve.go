@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/server"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	serveInputDir string
+	serveAddr     string
+	pollInterval  time.Duration
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "生成したドキュメントをローカルのHTTPサーバーでプレビューします。",
+	Long: `指定されたディレクトリ (省略時は設定ファイルの入力ディレクトリ) のOpenAPI仕様ファイルを解析し、生成したJSONと静的HTMLページをHTTPで配信します。
+仕様ファイルの変更を定期的に検知して変更されたファイルだけを再解析し、ブラウザを自動でリロードします。`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dirs := inputDirs(cmd, "input", serveInputDir)
+		fmt.Printf("ドキュメントを生成しています (入力: %s)\n", strings.Join(dirs, ", "))
+
+		srv, err := server.New(dirs, pollInterval, cfg.APIs.IncludesAPI)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Ctrl-C で停止したときも一時ディレクトリを削除できるよう、シグナルを受けたら正常に終了する
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("\n✅ プレビューサーバーを起動しました: http://localhost%s/\n", serveAddr)
+		fmt.Printf("JSONデータ: http://localhost%s/data/api-data.json\n", serveAddr)
+		err = srv.ListenAndServe(ctx, serveAddr)
+		if closeErr := srv.Close(); closeErr != nil {
+			fmt.Fprintf(os.Stderr, "エラー: 一時ディレクトリの削除に失敗しました: %v\n", closeErr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("プレビューサーバーを停止しました。")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().StringVarP(&serveInputDir, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	serveCmd.Flags().StringVarP(&serveAddr, "addr", "a", ":8080", "待ち受けるアドレス")
+	serveCmd.Flags().DurationVar(&pollInterval, "interval", time.Second, "ファイルの変更を確認する間隔")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/coverage"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"io"
+	"os"
+	"strings"
+)
+
+var (
+	statsInput  string
+	statsAPIs   []string
+	statsFormat string
+	statsOutput string
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "バージョンごとのドキュメントの充実度を表で出力します。",
+	Long: `入力ディレクトリ (-i) の API の全バージョンについて、summary・description・タグのあるオペレーション、
+説明のあるパラメータ、例のあるスキーマとレスポンスの割合を計算し、1つ前のバージョンからの増減とともに出力します。`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := coverage.ValidateFormat(statsFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		docs, err := parseInputDirs(inputDirs(cmd, "input", statsInput))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+		siteData, err := generator.Aggregate(docs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+
+		reports := generator.CoverageReports(siteData, statsAPIs)
+		if len(reports) == 0 {
+			fmt.Fprintln(os.Stderr, "エラー: 対象の API が見つかりません")
+			os.Exit(1)
+		}
+		if err := writeStats(reports); err != nil {
+			fmt.Fprintf(os.Stderr, "エラー: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeStats は充実度の表を --output (未指定なら標準出力) に書き出します。
+func writeStats(reports []coverage.APIReport) error {
+	var w io.Writer = os.Stdout
+	if statsOutput != "" {
+		f, err := os.Create(statsOutput)
+		if err != nil {
+			return fmt.Errorf("出力ファイルの作成に失敗しました: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+	return coverage.Write(w, reports, statsFormat)
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().StringVarP(&statsInput, "input", "i", "", "OpenAPIファイルが含まれるソースディレクトリ (省略時は設定ファイルの layout.workDir と sources[].dir)")
+	statsCmd.Flags().StringSliceVar(&statsAPIs, "api", nil, "対象の API 名 (複数指定可。省略時は全て)")
+	statsCmd.Flags().StringVarP(&statsFormat, "format", "f", coverage.FormatText, "出力フォーマット ("+strings.Join(coverage.Formats, ", ")+")")
+	statsCmd.Flags().StringVarP(&statsOutput, "output", "o", "", "出力先ファイル (未指定なら標準出力)")
+}
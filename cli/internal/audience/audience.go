@@ -0,0 +1,358 @@
+package audience
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"slices"
+)
+
+// 公開範囲を指定する拡張
+const (
+	// InternalExtension が true の要素は内部向けの出力にだけ含めます。
+	InternalExtension = "x-internal"
+	// AudienceExtension は要素を含める対象者の一覧です (例: [partner, public])。
+	AudienceExtension = "x-audience"
+)
+
+// Internal は全ての要素を含める内部向けの対象者です。
+const Internal = "internal"
+
+// Visible は拡張の値から、要素を audience 向けの出力に含めるかを返します。
+// 内部向けの出力には全てを含め、それ以外では x-internal: true の要素と x-audience に audience を含まない要素を除きます。
+func Visible(extensions map[string]any, audience string) bool {
+	if audience == Internal {
+		return true
+	}
+	if internal, _ := extensions[InternalExtension].(bool); internal {
+		return false
+	}
+	value, ok := extensions[AudienceExtension]
+	if !ok {
+		return true
+	}
+	switch audiences := value.(type) {
+	case string:
+		return audiences == audience
+	case []any:
+		for _, a := range audiences {
+			if a == audience {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Filter は doc を複製し、audience に公開しないオペレーション、パラメータ、スキーマ、プロパティを取り除いて返します。
+// 非公開のオペレーションからしか参照されなくなったスキーマとタグも取り除き、取り除いたスキーマへの参照は
+// 参照元のプロパティや合成先ごと取り除きます。doc 自体は変更しません。
+func Filter(doc *openapi3.T, audience string) (*openapi3.T, error) {
+	filtered, err := clone(doc)
+	if err != nil {
+		return nil, fmt.Errorf("仕様書の複製に失敗しました: %w", err)
+	}
+
+	schemasBefore, tagsBefore := references(filtered)
+	removed := make(map[string]bool)
+	var f *filter
+	// スキーマを取り除くと、その参照を持つプロパティが取り除かれて別のスキーマが参照されなくなることがあるため、
+	// 取り除くスキーマがなくなるまで繰り返す
+	for {
+		f = &filter{audience: audience, visited: make(map[*openapi3.Schema]bool), removed: removed}
+		f.components(filtered.Components)
+		f.paths(filtered.Paths)
+		schemasAfter, _ := references(filtered)
+
+		// 公開するオペレーションから参照されなくなったスキーマだけを取り除き、
+		// もともとどのオペレーションからも参照されていないスキーマは残す
+		changed := false
+		if filtered.Components != nil {
+			for name := range schemasBefore {
+				if _, ok := filtered.Components.Schemas[name]; ok && !schemasAfter[name] {
+					delete(filtered.Components.Schemas, name)
+					removed[name] = true
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	_, tagsAfter := references(filtered)
+	filtered.Tags = slices.DeleteFunc(filtered.Tags, func(tag *openapi3.Tag) bool {
+		return tag == nil || !f.visible(tag.Extensions) || (tagsBefore[tag.Name] && !tagsAfter[tag.Name])
+	})
+
+	// 取り除いた要素への参照が残っていないことを、読み込み直して確認する
+	if _, err := clone(filtered); err != nil {
+		return nil, fmt.Errorf("絞り込んだ仕様書の検証に失敗しました: %w", err)
+	}
+	return filtered, nil
+}
+
+// clone は仕様書を JSON に書き出して読み込み直した複製を返します。
+func clone(doc *openapi3.T) (*openapi3.T, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	return openapi3.NewLoader().LoadFromData(data)
+}
+
+type filter struct {
+	audience string
+	// visited は処理済みのスキーマです。参照先のスキーマは共有されているため1度だけ処理します。
+	visited map[*openapi3.Schema]bool
+	// removed は components.schemas から取り除いたスキーマ名です。
+	removed map[string]bool
+}
+
+func (f *filter) visible(extensions map[string]any) bool {
+	return Visible(extensions, f.audience)
+}
+
+// schemaVisible はスキーマを公開するかを返します。取り除いたスキーマへの参照と、
+// 要素の型が公開しないスキーマの配列は公開しません。
+func (f *filter) schemaVisible(ref *openapi3.SchemaRef) bool {
+	if ref == nil {
+		return true
+	}
+	if f.removed[specutil.SchemaNameFromRef(ref.Ref)] {
+		return false
+	}
+	if ref.Value == nil {
+		return true
+	}
+	if items := ref.Value.Items; items != nil && (f.removed[specutil.SchemaNameFromRef(items.Ref)] || (items.Value != nil && !f.visible(items.Value.Extensions))) {
+		return false
+	}
+	return f.visible(ref.Value.Extensions)
+}
+
+func (f *filter) components(components *openapi3.Components) {
+	if components == nil {
+		return
+	}
+	for name, ref := range components.Schemas {
+		if !f.schemaVisible(ref) {
+			delete(components.Schemas, name)
+			f.removed[name] = true
+			continue
+		}
+		f.schema(ref)
+	}
+	for name, ref := range components.Parameters {
+		if !f.parameterVisible(ref) {
+			delete(components.Parameters, name)
+			continue
+		}
+		f.schema(ref.Value.Schema)
+	}
+	for _, ref := range components.RequestBodies {
+		if ref != nil && ref.Value != nil {
+			f.content(ref.Value.Content)
+		}
+	}
+	for _, ref := range components.Responses {
+		f.response(ref)
+	}
+}
+
+func (f *filter) paths(paths *openapi3.Paths) {
+	if paths == nil {
+		return
+	}
+	for path, pathItem := range paths.Map() {
+		if pathItem == nil {
+			continue
+		}
+		for method, op := range pathItem.Operations() {
+			if !f.visible(op.Extensions) {
+				pathItem.SetOperation(method, nil)
+				continue
+			}
+			f.operation(op)
+		}
+		if len(pathItem.Operations()) == 0 {
+			paths.Delete(path)
+			continue
+		}
+		pathItem.Parameters = f.parameters(pathItem.Parameters)
+	}
+}
+
+func (f *filter) operation(op *openapi3.Operation) {
+	op.Parameters = f.parameters(op.Parameters)
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		f.content(op.RequestBody.Value.Content)
+	}
+	if op.Responses != nil {
+		for _, ref := range op.Responses.Map() {
+			f.response(ref)
+		}
+	}
+}
+
+// parameters は公開しないパラメータと、公開しないスキーマを型に持つパラメータを取り除きます。
+func (f *filter) parameters(params openapi3.Parameters) openapi3.Parameters {
+	params = slices.DeleteFunc(params, func(ref *openapi3.ParameterRef) bool {
+		return !f.parameterVisible(ref)
+	})
+	for _, ref := range params {
+		if ref != nil && ref.Value != nil {
+			f.schema(ref.Value.Schema)
+		}
+	}
+	return params
+}
+
+func (f *filter) parameterVisible(ref *openapi3.ParameterRef) bool {
+	if ref == nil || ref.Value == nil {
+		return true
+	}
+	return f.visible(ref.Value.Extensions) && f.schemaVisible(ref.Value.Schema)
+}
+
+func (f *filter) response(ref *openapi3.ResponseRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	f.content(ref.Value.Content)
+	for name, header := range ref.Value.Headers {
+		if header == nil || header.Value == nil {
+			continue
+		}
+		if !f.visible(header.Value.Extensions) || !f.schemaVisible(header.Value.Schema) {
+			delete(ref.Value.Headers, name)
+			continue
+		}
+		f.schema(header.Value.Schema)
+	}
+}
+
+// content は公開しないスキーマを型に持つメディアタイプを取り除きます。
+func (f *filter) content(content openapi3.Content) {
+	for mimeType, mediaType := range content {
+		if mediaType == nil {
+			continue
+		}
+		if !f.schemaVisible(mediaType.Schema) {
+			delete(content, mimeType)
+			continue
+		}
+		f.schema(mediaType.Schema)
+	}
+}
+
+// schema はスキーマから公開しないプロパティと合成先のスキーマを再帰的に取り除きます。
+func (f *filter) schema(ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil || f.visited[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	f.visited[schema] = true
+
+	for name, prop := range schema.Properties {
+		if !f.schemaVisible(prop) {
+			delete(schema.Properties, name)
+			schema.Required = slices.DeleteFunc(schema.Required, func(required string) bool { return required == name })
+			continue
+		}
+		f.schema(prop)
+	}
+	schema.AllOf = f.schemas(schema.AllOf)
+	schema.OneOf = f.schemas(schema.OneOf)
+	schema.AnyOf = f.schemas(schema.AnyOf)
+	f.schema(schema.Items)
+	if !f.schemaVisible(schema.Not) {
+		schema.Not = nil
+	}
+	f.schema(schema.Not)
+	if !f.schemaVisible(schema.AdditionalProperties.Schema) {
+		schema.AdditionalProperties.Schema = nil
+	}
+	f.schema(schema.AdditionalProperties.Schema)
+}
+
+func (f *filter) schemas(refs openapi3.SchemaRefs) openapi3.SchemaRefs {
+	refs = slices.DeleteFunc(refs, func(ref *openapi3.SchemaRef) bool { return !f.schemaVisible(ref) })
+	for _, ref := range refs {
+		f.schema(ref)
+	}
+	return refs
+}
+
+// references はオペレーションから直接または間接に参照されているスキーマ名と、オペレーションに付いているタグを返します。
+func references(doc *openapi3.T) (schemas map[string]bool, tags map[string]bool) {
+	r := &referenceCollector{schemas: make(map[string]bool), visited: make(map[*openapi3.Schema]bool)}
+	tags = make(map[string]bool)
+	for _, op := range specutil.Operations(doc) {
+		for _, tag := range op.Operation.Tags {
+			tags[tag] = true
+		}
+		for _, param := range op.Parameters() {
+			r.schema(param.Value.Schema)
+		}
+		if op.Operation.RequestBody != nil && op.Operation.RequestBody.Value != nil {
+			r.content(op.Operation.RequestBody.Value.Content)
+		}
+		if op.Operation.Responses == nil {
+			continue
+		}
+		for _, ref := range op.Operation.Responses.Map() {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			r.content(ref.Value.Content)
+			for _, header := range ref.Value.Headers {
+				if header != nil && header.Value != nil {
+					r.schema(header.Value.Schema)
+				}
+			}
+		}
+	}
+	return r.schemas, tags
+}
+
+type referenceCollector struct {
+	schemas map[string]bool
+	visited map[*openapi3.Schema]bool
+}
+
+func (r *referenceCollector) content(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			r.schema(mediaType.Schema)
+		}
+	}
+}
+
+func (r *referenceCollector) schema(ref *openapi3.SchemaRef) {
+	if ref == nil {
+		return
+	}
+	if name := specutil.SchemaNameFromRef(ref.Ref); name != "" {
+		r.schemas[name] = true
+	}
+	if ref.Value == nil || r.visited[ref.Value] {
+		return
+	}
+	schema := ref.Value
+	r.visited[schema] = true
+	for _, prop := range schema.Properties {
+		r.schema(prop)
+	}
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.OneOf, schema.AnyOf} {
+		for _, sub := range refs {
+			r.schema(sub)
+		}
+	}
+	r.schema(schema.Items)
+	r.schema(schema.Not)
+	r.schema(schema.AdditionalProperties.Schema)
+}
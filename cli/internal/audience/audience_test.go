@@ -0,0 +1,218 @@
+package audience
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"slices"
+	"sort"
+	"testing"
+)
+
+const filterSpec = `
+openapi: 3.0.3
+info: {title: t, version: 1.0.0}
+tags:
+  - name: pets
+  - name: admin
+paths:
+  /pets:
+    get:
+      tags: [pets]
+      parameters:
+        - {name: limit, in: query, schema: {type: integer}}
+        - {name: debug, in: query, x-internal: true, schema: {type: boolean}}
+        - {name: offset, in: query, x-audience: [partner], schema: {type: integer}}
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Pet'}
+    post:
+      x-audience: partner
+      responses:
+        "201": {description: created}
+  /admin/stats:
+    get:
+      tags: [admin]
+      x-internal: true
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Stats'}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, secret]
+      properties:
+        id: {type: integer}
+        secret: {type: string, x-internal: true}
+    Stats:
+      type: object
+      properties:
+        count: {$ref: '#/components/schemas/Count'}
+    Count:
+      type: integer
+    Orphan:
+      type: object
+      properties:
+        s: {$ref: '#/components/schemas/Stats'}
+        list:
+          type: array
+          items: {$ref: '#/components/schemas/Stats'}
+        name: {type: string}
+    Hidden:
+      type: object
+      x-internal: true
+`
+
+func loadSpec(t *testing.T, spec string) *openapi3.T {
+	t.Helper()
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(spec))
+	if err != nil {
+		t.Fatalf("仕様の読み込みに失敗しました: %v", err)
+	}
+	return doc
+}
+
+func TestVisible(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]any
+		audience   string
+		want       bool
+	}{
+		{"拡張なし", nil, "public", true},
+		{"x-internal は公開しない", map[string]any{InternalExtension: true}, "public", false},
+		{"x-internal: false は公開する", map[string]any{InternalExtension: false}, "public", true},
+		{"internal には全て公開する", map[string]any{InternalExtension: true}, Internal, true},
+		{"x-audience に含まれる", map[string]any{AudienceExtension: []any{"partner", "public"}}, "public", true},
+		{"x-audience に含まれない", map[string]any{AudienceExtension: []any{"partner"}}, "public", false},
+		{"x-audience が文字列", map[string]any{AudienceExtension: "partner"}, "partner", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Visible(tt.extensions, tt.audience); got != tt.want {
+				t.Errorf("Visible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		audience   string
+		operations []string
+		params     []string
+		schemas    []string
+		orphan     []string
+		tags       []string
+	}{
+		{
+			audience:   Internal,
+			operations: []string{"GET /admin/stats", "GET /pets", "POST /pets"},
+			params:     []string{"limit", "debug", "offset"},
+			schemas:    []string{"Count", "Hidden", "Orphan", "Pet", "Stats"},
+			orphan:     []string{"list", "name", "s"},
+			tags:       []string{"pets", "admin"},
+		},
+		{
+			audience:   "partner",
+			operations: []string{"GET /pets", "POST /pets"},
+			params:     []string{"limit", "offset"},
+			schemas:    []string{"Orphan", "Pet"},
+			orphan:     []string{"name"},
+			tags:       []string{"pets"},
+		},
+		{
+			audience:   "public",
+			operations: []string{"GET /pets"},
+			params:     []string{"limit"},
+			schemas:    []string{"Orphan", "Pet"},
+			orphan:     []string{"name"},
+			tags:       []string{"pets"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.audience, func(t *testing.T) {
+			doc := loadSpec(t, filterSpec)
+			filtered, err := Filter(doc, tt.audience)
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+
+			var operations []string
+			for path, item := range filtered.Paths.Map() {
+				for method := range item.Operations() {
+					operations = append(operations, method+" "+path)
+				}
+			}
+			sort.Strings(operations)
+			if !slices.Equal(operations, tt.operations) {
+				t.Errorf("operations = %v, want %v", operations, tt.operations)
+			}
+
+			var params []string
+			for _, p := range filtered.Paths.Value("/pets").Get.Parameters {
+				params = append(params, p.Value.Name)
+			}
+			if !slices.Equal(params, tt.params) {
+				t.Errorf("parameters = %v, want %v", params, tt.params)
+			}
+
+			if got := sortedKeys(filtered.Components.Schemas); !slices.Equal(got, tt.schemas) {
+				t.Errorf("schemas = %v, want %v", got, tt.schemas)
+			}
+			if got := sortedKeys(filtered.Components.Schemas["Orphan"].Value.Properties); !slices.Equal(got, tt.orphan) {
+				t.Errorf("Orphan properties = %v, want %v", got, tt.orphan)
+			}
+
+			var tags []string
+			for _, tag := range filtered.Tags {
+				tags = append(tags, tag.Name)
+			}
+			if !slices.Equal(tags, tt.tags) {
+				t.Errorf("tags = %v, want %v", tags, tt.tags)
+			}
+
+			// 絞り込んだ仕様書は参照が解決でき、検証も通ること
+			if _, err := clone(filtered); err != nil {
+				t.Errorf("絞り込んだ仕様書を読み込み直せません: %v", err)
+			}
+			if err := filtered.Validate(openapi3.NewLoader().Context); err != nil {
+				t.Errorf("絞り込んだ仕様書の検証に失敗しました: %v", err)
+			}
+		})
+	}
+}
+
+func TestFilterRemovesRequiredHiddenProperty(t *testing.T) {
+	filtered, err := Filter(loadSpec(t, filterSpec), "public")
+	if err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if got := filtered.Components.Schemas["Pet"].Value.Required; !slices.Equal(got, []string{"id"}) {
+		t.Errorf("Pet.required = %v, want [id]", got)
+	}
+}
+
+func TestFilterDoesNotModifyInput(t *testing.T) {
+	doc := loadSpec(t, filterSpec)
+	if _, err := Filter(doc, "public"); err != nil {
+		t.Fatalf("Filter() error = %v", err)
+	}
+	if doc.Paths.Value("/admin/stats") == nil || doc.Components.Schemas["Stats"] == nil {
+		t.Error("Filter() が元の仕様書を変更しました")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
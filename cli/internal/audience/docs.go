@@ -0,0 +1,65 @@
+package audience
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+)
+
+// Options は対象者ごとのドキュメントの差分と lint の結果を計算し直す設定です。
+type Options struct {
+	Localizer *diff.Localizer
+	Rules     *diff.Rules
+	// Ruleset は絞り込んだ仕様書を検査するルールです。nil なら lint の結果を含めません。
+	Ruleset *lint.Ruleset
+}
+
+// FilterDocs は docs を audience 向けに絞り込んだ複製を返します。
+// 差分は公開しない要素の変更を含まないよう、元のドキュメントと同じバージョンの組について計算し直します。
+// lint の結果も公開しない要素の問題を含まないよう、絞り込んだ仕様書を検査し直します。
+func FilterDocs(docs []*parser.APIDocument, audience string, opts Options) ([]*parser.APIDocument, error) {
+	filtered := make([]*parser.APIDocument, 0, len(docs))
+	byVersion := make(map[string]map[string]*parser.APIDocument)
+	for _, doc := range docs {
+		spec, err := Filter(doc.Doc, audience)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s の絞り込みに失敗しました: %w", doc.APIName, doc.Version, err)
+		}
+		copied := &parser.APIDocument{
+			Path:    doc.Path,
+			APIName: doc.APIName,
+			Version: doc.Version,
+			Info:    doc.Info,
+			Doc:     spec,
+		}
+		if opts.Ruleset != nil {
+			copied.Lint = opts.Ruleset.Lint(spec)
+		}
+		filtered = append(filtered, copied)
+		if byVersion[doc.APIName] == nil {
+			byVersion[doc.APIName] = make(map[string]*parser.APIDocument)
+		}
+		byVersion[doc.APIName][doc.Version] = copied
+	}
+
+	for i, doc := range docs {
+		copied := filtered[i]
+		copied.Diffs = downloader.Diffs{}
+		copied.StructuralDiffs = downloader.StructuralDiffs{}
+		for version := range doc.Diffs {
+			revision, ok := byVersion[doc.APIName][version]
+			if !ok {
+				continue
+			}
+			changes, structural, err := diff.CompareDocs(copied, revision, opts.Localizer)
+			if err != nil {
+				return nil, fmt.Errorf("%s の %s から %s への差分の計算に失敗しました: %w", doc.APIName, doc.Version, version, err)
+			}
+			copied.Diffs[version] = opts.Rules.Apply(changes, diff.SpecVersion(copied), diff.SpecVersion(revision))
+			copied.StructuralDiffs[version] = structural
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,233 @@
+package changelog
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 変更のグループ
+const (
+	GroupBreaking   = "breaking"
+	GroupDeprecated = "deprecated"
+	GroupAdded      = "added"
+	GroupChanged    = "changed"
+	GroupDocs       = "docs"
+)
+
+// groupOrder はリリースノートでのグループの並び順です。
+var groupOrder = []string{GroupBreaking, GroupDeprecated, GroupAdded, GroupChanged, GroupDocs}
+
+// groupTitles はグループの見出しです。
+var groupTitles = map[string]string{
+	GroupBreaking:   "互換性を損なう変更",
+	GroupDeprecated: "非推奨",
+	GroupAdded:      "追加",
+	GroupChanged:    "変更",
+	GroupDocs:       "ドキュメントの修正",
+}
+
+// Changelog は1つの API のバージョンの範囲のリリースノートです。
+type Changelog struct {
+	API  string `json:"api"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Releases は新しいバージョンから順に並びます
+	Releases []Release `json:"releases"`
+}
+
+// Release は1つのバージョンで、1つ前のバージョンから行われた変更です。
+type Release struct {
+	Version  string    `json:"version"`
+	Previous string    `json:"previous"`
+	Date     time.Time `json:"date"`
+	// Groups は変更のあるグループだけを groupOrder の順に含みます
+	Groups []Group `json:"groups"`
+}
+
+// Group は種類ごとにまとめた変更です。
+type Group struct {
+	Key     string  `json:"key"`
+	Title   string  `json:"title"`
+	Entries []Entry `json:"entries"`
+}
+
+// Entry はリリースノートの1行です。
+type Entry struct {
+	ID           string `json:"id,omitempty"`
+	Level        string `json:"level,omitempty"`
+	Operation    string `json:"operation,omitempty"`
+	Path         string `json:"path,omitempty"`
+	Section      string `json:"section,omitempty"`
+	Text         string `json:"text"`
+	Comment      string `json:"comment,omitempty"`
+	Acknowledged bool   `json:"acknowledged,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// Options はリリースノートを作る範囲と、差分が未計算のバージョンの組を比較する設定です。
+type Options struct {
+	// From は範囲の起点のバージョンです (このバージョン自体の変更は含みません)。空なら To の1つ前です。
+	From string
+	// To は範囲の終点のバージョンです。空なら最新のバージョンです。
+	To        string
+	Localizer *diff.Localizer
+	Rules     *diff.Rules
+}
+
+// Build は docs のうち apiName のバージョンを並べ、From から To までの連続するバージョンの変更をグループにまとめます。
+// diff.json に差分がない組はその場で比較します。
+func Build(docs []*parser.APIDocument, apiName string, opts Options) (*Changelog, error) {
+	var versions []*parser.APIDocument
+	for _, doc := range docs {
+		if doc.APIName == apiName {
+			versions = append(versions, doc)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("API '%s' が見つかりません", apiName)
+	}
+	sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i].Version, versions[j].Version) < 0 })
+
+	to := len(versions) - 1
+	if opts.To != "" {
+		if to = indexOf(versions, opts.To); to < 0 {
+			return nil, fmt.Errorf("API '%s' にバージョン '%s' が見つかりません", apiName, opts.To)
+		}
+	}
+	from := to - 1
+	if opts.From != "" {
+		if from = indexOf(versions, opts.From); from < 0 {
+			return nil, fmt.Errorf("API '%s' にバージョン '%s' が見つかりません", apiName, opts.From)
+		}
+	}
+	if from < 0 {
+		return nil, fmt.Errorf("API '%s' に %s より前のバージョンがありません", apiName, versions[to].Version)
+	}
+	if from >= to {
+		return nil, fmt.Errorf("起点のバージョン %s は終点のバージョン %s より前である必要があります", versions[from].Version, versions[to].Version)
+	}
+
+	cl := &Changelog{API: apiName, From: versions[from].Version, To: versions[to].Version, Releases: []Release{}}
+	for i := to; i > from; i-- {
+		release, err := buildRelease(versions[i-1], versions[i], opts)
+		if err != nil {
+			return nil, err
+		}
+		cl.Releases = append(cl.Releases, release)
+	}
+	return cl, nil
+}
+
+// buildRelease は prev から cur への変更をグループにまとめます。
+func buildRelease(prev *parser.APIDocument, cur *parser.APIDocument, opts Options) (Release, error) {
+	// P から V への変更は P の diff.json の V に記録されている
+	changes, ok := prev.Diffs[cur.Version]
+	structural := prev.StructuralDiffs[cur.Version]
+	if !ok {
+		var err error
+		changes, structural, err = diff.CompareDocs(prev, cur, opts.Localizer)
+		if err != nil {
+			return Release{}, fmt.Errorf("%s から %s への差分の計算に失敗しました: %w", prev.Version, cur.Version, err)
+		}
+		changes = opts.Rules.Apply(changes, diff.SpecVersion(prev), diff.SpecVersion(cur))
+	}
+
+	entries := make(map[string][]Entry)
+	for _, change := range changes {
+		key := Classify(change)
+		entries[key] = append(entries[key], Entry{
+			ID:           change.Id,
+			Level:        change.LevelName(),
+			Operation:    change.Operation,
+			Path:         change.Path,
+			Section:      change.Section,
+			Text:         change.Text,
+			Comment:      change.Comment,
+			Acknowledged: change.Acknowledged,
+			Reason:       change.Reason,
+		})
+	}
+	entries[GroupDocs] = append(entries[GroupDocs], docEntries(structural)...)
+
+	release := Release{Version: cur.Version, Previous: prev.Version, Date: cur.Info.Date, Groups: []Group{}}
+	for _, key := range groupOrder {
+		if len(entries[key]) == 0 {
+			continue
+		}
+		release.Groups = append(release.Groups, Group{Key: key, Title: groupTitles[key], Entries: entries[key]})
+	}
+	return release, nil
+}
+
+// Classify は変更が属するグループを返します。
+// レベルを優先し、互換性を損なう変更 (downloader.BreakingLevel 以上) を分け、それ以外は ID から非推奨・追加・変更に分けます。
+// 説明文や例などドキュメントの修正は構造的な差分から docEntries で集めます。
+func Classify(change downloader.Change) string {
+	switch {
+	case change.IsBreaking():
+		return GroupBreaking
+	case strings.Contains(change.Id, "deprecated"):
+		return GroupDeprecated
+	case strings.Contains(change.Id, "added") || strings.HasPrefix(change.Id, "new-"):
+		return GroupAdded
+	default:
+		return GroupChanged
+	}
+}
+
+// docEntries は構造的な差分のうち、説明文や例など互換性の判定に関係しない項目の変更を返します。
+func docEntries(structural downloader.StructuralDiff) []Entry {
+	var entries []Entry
+	for _, path := range sortedKeys(structural.Operations) {
+		methods := structural.Operations[path]
+		for _, method := range sortedKeys(methods) {
+			for _, field := range methods[method] {
+				if !diff.IsDocField(field) {
+					continue
+				}
+				entries = append(entries, Entry{
+					Operation: method,
+					Path:      path,
+					Section:   "paths",
+					Text:      fmt.Sprintf("%s を更新しました", strings.Join(field.Path, " › ")),
+				})
+			}
+		}
+	}
+	for _, name := range sortedKeys(structural.Schemas) {
+		for _, field := range structural.Schemas[name] {
+			if !diff.IsDocField(field) {
+				continue
+			}
+			entries = append(entries, Entry{
+				Section: "components",
+				Text:    fmt.Sprintf("スキーマ %s の %s を更新しました", name, strings.Join(field.Path, " › ")),
+			})
+		}
+	}
+	return entries
+}
+
+func indexOf(versions []*parser.APIDocument, version string) int {
+	for i, doc := range versions {
+		if doc.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
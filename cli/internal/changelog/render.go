@@ -0,0 +1,128 @@
+package changelog
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/htmlsite"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// リリースノートの出力フォーマット
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatJSON     = "json"
+)
+
+// Formats は Write が対応している出力フォーマットです。
+var Formats = []string{FormatMarkdown, FormatHTML, FormatJSON}
+
+//go:embed templates
+var templateFS embed.FS
+
+// ValidateFormat は出力フォーマットが対応しているかを検証します。
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応の出力フォーマットです: %s (%s)", format, strings.Join(Formats, ", "))
+}
+
+// Write はリリースノートを format で書き出します。
+// templateFile を指定した場合は markdown と html で組み込みのテンプレートの代わりに使います (json では使いません)。
+// テンプレートには Changelog が渡され、date 関数で日付を YYYY-MM-DD 形式にできます。
+func Write(w io.Writer, cl *Changelog, format string, templateFile string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cl)
+	case FormatMarkdown:
+		return writeMarkdown(w, cl, templateFile)
+	case FormatHTML:
+		return writeHTML(w, cl, templateFile)
+	}
+	return ValidateFormat(format)
+}
+
+func writeMarkdown(w io.Writer, cl *Changelog, templateFile string) error {
+	name, text, err := readTemplate(templateFile, "templates/changelog.md.tmpl")
+	if err != nil {
+		return err
+	}
+	funcs := texttemplate.FuncMap{
+		"date":   formatDate,
+		"inline": inline,
+	}
+	t, err := texttemplate.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("テンプレート '%s' の読み込みに失敗しました: %w", name, err)
+	}
+	if err := t.Execute(w, cl); err != nil {
+		return fmt.Errorf("Markdownの書き出しに失敗しました: %w", err)
+	}
+	return nil
+}
+
+func writeHTML(w io.Writer, cl *Changelog, templateFile string) error {
+	name, text, err := readTemplate(templateFile, "templates/changelog.html.tmpl")
+	if err != nil {
+		return err
+	}
+	style, err := htmlsite.Stylesheet()
+	if err != nil {
+		return err
+	}
+	funcs := htmltemplate.FuncMap{
+		"date":  formatDate,
+		"lower": strings.ToLower,
+		"style": func() htmltemplate.CSS { return style },
+	}
+	t, err := htmltemplate.New(name).Funcs(funcs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("テンプレート '%s' の読み込みに失敗しました: %w", name, err)
+	}
+	if err := t.Execute(w, cl); err != nil {
+		return fmt.Errorf("HTMLの書き出しに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// readTemplate は templateFile が指定されていればそのファイルを、なければ組み込みのテンプレート builtin を読み込みます。
+func readTemplate(templateFile string, builtin string) (name string, text string, err error) {
+	if templateFile == "" {
+		data, err := templateFS.ReadFile(builtin)
+		if err != nil {
+			return "", "", fmt.Errorf("組み込みのテンプレートの読み込みに失敗しました: %w", err)
+		}
+		return filepath.Base(builtin), string(data), nil
+	}
+	data, err := os.ReadFile(templateFile)
+	if err != nil {
+		return "", "", fmt.Errorf("テンプレートファイルの読み込みに失敗しました: %w", err)
+	}
+	return filepath.Base(templateFile), string(data), nil
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// inline は改行を含む文字列を Markdown のリストの1行にまとめます。
+func inline(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,151 @@
+package check
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"io"
+	"sort"
+)
+
+// Result は2つの仕様の後方互換性チェックの結果です。
+type Result struct {
+	Base     string
+	Revision string
+	// Changes はレベルの高い順に並べた全ての変更
+	Changes downloader.Diff
+	// FailOn はこのレベル以上の変更があれば失敗とみなすレベル
+	FailOn int
+	// VersionCheck は info.version の上げ方の検証結果です。バージョンとして解釈できない場合は nil です。
+	VersionCheck *diff.VersionCheck
+}
+
+// Run は base から revision への変更を検出し、rules を適用します。
+// base と revision には diff.LoadSpec が受け付ける指定を使えます。
+// rules のバージョンの範囲は各仕様の info.version と照合します。
+func Run(base string, revision string, failOn int, localizer *diff.Localizer, rules *diff.Rules) (*Result, error) {
+	baseSpec, err := diff.LoadSpec(base)
+	if err != nil {
+		return nil, err
+	}
+	revisionSpec, err := diff.LoadSpec(revision)
+	if err != nil {
+		return nil, err
+	}
+	changes, structural, err := diff.CompareSpecs(baseSpec, revisionSpec, localizer)
+	if err != nil {
+		return nil, fmt.Errorf("差分の計算に失敗しました: %w", err)
+	}
+	changes = rules.Apply(changes, baseSpec.Version, revisionSpec.Version)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Level > changes[j].Level })
+
+	result := &Result{Base: base, Revision: revision, Changes: changes, FailOn: failOn}
+	if check, ok := diff.CheckVersion(baseSpec.Version, revisionSpec.Version, changes, structural); ok {
+		result.VersionCheck = &check
+	}
+	return result, nil
+}
+
+// IsFailure は change が失敗とみなすレベル以上で、承認済みでないかを返します。
+func (r *Result) IsFailure(change downloader.Change) bool {
+	return change.Level >= r.FailOn && !change.Acknowledged
+}
+
+// Failures は失敗とみなす変更を返します。
+func (r *Result) Failures() downloader.Diff {
+	var failures downloader.Diff
+	for _, change := range r.Changes {
+		if r.IsFailure(change) {
+			failures = append(failures, change)
+		}
+	}
+	return failures
+}
+
+// Failed は失敗とみなす変更が1件以上あるかを返します。
+func (r *Result) Failed() bool {
+	return len(r.Failures()) > 0
+}
+
+// Counts はレベルごとの変更数を返します。承認済みの変更も含みます。
+func (r *Result) Counts() map[int]int {
+	counts := make(map[int]int)
+	for _, change := range r.Changes {
+		counts[change.Level]++
+	}
+	return counts
+}
+
+// Acknowledged は承認済みの変更の数を返します。
+func (r *Result) Acknowledged() int {
+	n := 0
+	for _, change := range r.Changes {
+		if change.Acknowledged {
+			n++
+		}
+	}
+	return n
+}
+
+// Title は比較対象を表す見出しを返します。
+func (r *Result) Title() string {
+	return fmt.Sprintf("%s → %s", r.Base, r.Revision)
+}
+
+// WriteText はレベルごとの変更数と変更の一覧をテキストで書き出します。
+func (r *Result) WriteText(w io.Writer) {
+	counts := r.Counts()
+	fmt.Fprintln(w, r.Title())
+	fmt.Fprintf(w, "%d件の変更 (ERR: %d件, WARN: %d件, INFO: %d件, 承認済み: %d件)\n", len(r.Changes), counts[downloader.LevelErr], counts[downloader.LevelWarn], counts[downloader.LevelInfo], r.Acknowledged())
+	if len(r.Changes) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, change := range r.Changes {
+		fmt.Fprintf(w, "%-4s %s%s\n", change.LevelName(), change.Text, acknowledgedLabel(change))
+		if change.Operation != "" {
+			fmt.Fprintf(w, "     %s %s\n", change.Operation, change.Path)
+		}
+		if change.Comment != "" {
+			fmt.Fprintf(w, "     %s\n", change.Comment)
+		}
+	}
+	if r.VersionCheck != nil {
+		fmt.Fprintln(w)
+		r.writeVersionCheck(w)
+	}
+}
+
+// writeVersionCheck は必要なバージョンの上げ方と実際の上げ方を書き出します。
+func (r *Result) writeVersionCheck(w io.Writer) {
+	c := r.VersionCheck
+	fmt.Fprintf(w, "推奨されるバージョンの上げ方: %s (実際: %s, %s → %s)\n", c.Required, c.Actual, c.From, c.To)
+	if !c.OK {
+		fmt.Fprintf(w, "⚠ 変更の内容に対してバージョンの上げ方が不足しています。\n")
+	}
+}
+
+// acknowledgedLabel は承認済みの変更に付ける注記を返します。
+func acknowledgedLabel(change downloader.Change) string {
+	switch {
+	case !change.Acknowledged:
+		return ""
+	case change.Reason != "":
+		return fmt.Sprintf(" (承認済み: %s)", change.Reason)
+	default:
+		return " (承認済み)"
+	}
+}
+
+// WriteSummary は変更の一覧に続けて、失敗とみなす変更があったかを書き出します。
+func (r *Result) WriteSummary(w io.Writer) {
+	r.WriteText(w)
+	fmt.Fprintln(w)
+
+	failures := len(r.Failures())
+	threshold := downloader.Change{Level: r.FailOn}.LevelName()
+	if failures > 0 {
+		fmt.Fprintf(w, "❌ %s 以上の変更が%d件あります。\n", threshold, failures)
+	} else {
+		fmt.Fprintf(w, "✅ %s 以上の変更はありません。\n", threshold)
+	}
+}
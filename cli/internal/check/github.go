@@ -0,0 +1,55 @@
+package check
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"io"
+	"strings"
+)
+
+// WriteGitHubAnnotations は変更を GitHub Actions のワークフローコマンド形式で書き出します。
+// 失敗とみなす変更は error、それ以外は変更のレベルに応じて warning か notice になります。
+func WriteGitHubAnnotations(w io.Writer, r *Result) {
+	for _, change := range r.Changes {
+		properties := []string{"title=" + escapeProperty(change.Id)}
+		if file := diff.SourceFile(change.Source); file != "" {
+			properties = append([]string{"file=" + escapeProperty(file)}, properties...)
+		}
+		fmt.Fprintf(w, "::%s %s::%s\n", annotationLevel(r, change), strings.Join(properties, ","), escapeData(annotationMessage(change)))
+	}
+}
+
+func annotationLevel(r *Result, change downloader.Change) string {
+	switch {
+	case r.IsFailure(change):
+		return "error"
+	case change.Acknowledged:
+		return "notice"
+	case change.Level >= downloader.LevelWarn:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+func annotationMessage(change downloader.Change) string {
+	message := change.Text + acknowledgedLabel(change)
+	if change.Operation != "" {
+		message = fmt.Sprintf("%s %s: %s", change.Operation, change.Path, message)
+	}
+	if change.Comment != "" {
+		message += "\n" + change.Comment
+	}
+	return message
+}
+
+// escapeData はワークフローコマンドのメッセージ部分をエスケープします。
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty はワークフローコマンドのプロパティ値をエスケープします。
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
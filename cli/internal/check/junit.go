@@ -0,0 +1,73 @@
+package check
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit は結果を JUnit XML 形式で書き出します。
+// 変更ごとに1つのテストケースを作り、失敗とみなす変更を failure にします。
+// 変更がない場合は成功したテストケースを1つだけ出力します。
+func WriteJUnit(w io.Writer, r *Result) error {
+	suite := junitTestSuite{Name: fmt.Sprintf("%s → %s", r.Base, r.Revision)}
+	for _, change := range r.Changes {
+		className := change.Section
+		if change.Operation != "" {
+			className = fmt.Sprintf("%s %s", change.Operation, change.Path)
+		}
+		tc := junitTestCase{Name: change.Id, ClassName: className}
+		body := strings.TrimSpace(strings.Join([]string{change.Text, change.Comment}, "\n"))
+		if r.IsFailure(change) {
+			tc.Failure = &junitFailure{Message: change.Text, Type: change.LevelName(), Body: body}
+			suite.Failures++
+		} else {
+			tc.SystemOut = fmt.Sprintf("[%s] %s%s", change.LevelName(), body, acknowledgedLabel(change))
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	if len(suite.Cases) == 0 {
+		suite.Cases = append(suite.Cases, junitTestCase{Name: "backward-compatibility", ClassName: "check"})
+	}
+	suite.Tests = len(suite.Cases)
+
+	suites := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return fmt.Errorf("JUnit XML の書き出しに失敗しました: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
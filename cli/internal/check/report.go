@@ -0,0 +1,61 @@
+package check
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/htmlsite"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/markdown"
+	"io"
+	"strings"
+)
+
+// 変更の一覧の出力フォーマット
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatHTML     = "html"
+)
+
+// Formats は WriteReport が対応している出力フォーマットです。
+var Formats = []string{FormatText, FormatMarkdown, FormatJSON, FormatHTML}
+
+// ValidateFormat は出力フォーマットが対応しているかを検証します。
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応の出力フォーマットです: %s (%s)", format, strings.Join(Formats, ", "))
+}
+
+// WriteReport は変更の一覧を format で書き出します。
+func WriteReport(w io.Writer, r *Result, format string) error {
+	switch format {
+	case FormatText:
+		r.WriteText(w)
+		return nil
+	case FormatMarkdown:
+		_, err := io.WriteString(w, markdown.RenderChanges(r.Title(), r.Changes))
+		return err
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		report := struct {
+			Base         string             `json:"base"`
+			Revision     string             `json:"revision"`
+			Changes      downloader.Diff    `json:"changes"`
+			VersionCheck *diff.VersionCheck `json:"versionCheck,omitempty"`
+		}{Base: r.Base, Revision: r.Revision, Changes: r.Changes, VersionCheck: r.VersionCheck}
+		if report.Changes == nil {
+			report.Changes = downloader.Diff{}
+		}
+		return encoder.Encode(report)
+	case FormatHTML:
+		return htmlsite.WriteChanges(w, r.Title(), r.Changes)
+	}
+	return ValidateFormat(format)
+}
@@ -0,0 +1,339 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/output"
+	"gopkg.in/yaml.v3"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultFile は --config が指定されていない場合に読み込む設定ファイルの名前です。
+const DefaultFile = "openapi-docs.yaml"
+
+// envPrefix は設定を上書きする環境変数の接頭辞です。
+const envPrefix = "OASDOC_"
+
+// Config はCLI全体のプロジェクト設定です。
+type Config struct {
+	Version int       `yaml:"version"`
+	Sources []Source  `yaml:"sources"`
+	APIs    APIFilter `yaml:"apis"`
+	Layout  Layout    `yaml:"layout"`
+	Output  Output    `yaml:"output"`
+	Diff    Diff      `yaml:"diff"`
+	Lint    Lint      `yaml:"lint"`
+}
+
+// Source は仕様ファイルの取得元です。Repo と Dir のどちらか一方を指定します。
+type Source struct {
+	Repo        string `yaml:"repo,omitempty"`
+	MaxVersions int    `yaml:"maxVersions,omitempty"`
+	Dir         string `yaml:"dir,omitempty"`
+}
+
+// APIFilter は対象とするAPI名のグロブパターンです。Exclude は Include より優先されます。
+type APIFilter struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// Layout は入出力ディレクトリの配置です。
+type Layout struct {
+	WorkDir   string `yaml:"workDir"`
+	OutputDir string `yaml:"outputDir"`
+}
+
+// Output は生成するドキュメントの設定です。
+type Output struct {
+	Formats               []string `yaml:"formats"`
+	FailOnInvalidExamples bool     `yaml:"failOnInvalidExamples"`
+	// SiteURL は公開するサイトのURL。フィードのリンクを絶対URLにするために使います。
+	SiteURL string `yaml:"siteURL,omitempty"`
+	// Audiences は対象者ごとに絞り込んだサイトを outputDir/<対象者> に出力する対象者の一覧。空なら絞り込まずに outputDir に出力します。
+	Audiences []string `yaml:"audiences,omitempty"`
+}
+
+// Diff は差分計算の設定です。
+type Diff struct {
+	Enabled bool   `yaml:"enabled"`
+	Locale  string `yaml:"locale"`
+	// Translations は組み込みの翻訳を上書き・追加する翻訳ファイルのパス
+	Translations string `yaml:"translations,omitempty"`
+	// Rules は既知の変更の承認と重要度の上書きを定義するルールファイルのパス
+	Rules string `yaml:"rules,omitempty"`
+	// Strategy は差分を計算するバージョンの組み合わせ (consecutive, latest, previous-major, full)
+	Strategy string `yaml:"strategy"`
+	// Workers は同時に実行する差分計算の数。0 の場合は CPU 数を使います。
+	Workers int `yaml:"workers,omitempty"`
+}
+
+// Lint は仕様書のスタイルの検査の設定です。
+type Lint struct {
+	Enabled bool `yaml:"enabled"`
+	// Rules はルールのレベルの上書きとルールの設定を定義するルールファイルのパス
+	Rules string `yaml:"rules,omitempty"`
+	// FailOn は最新バージョンにこのレベル以上の問題があれば生成を失敗させるレベル (ERR, WARN, INFO)。空なら失敗させません。
+	FailOn string `yaml:"failOn,omitempty"`
+}
+
+// Default は設定ファイルがない場合の既定値を返します。
+func Default() *Config {
+	return &Config{
+		Version: 1,
+		Layout: Layout{
+			WorkDir:   "downloaded_apis",
+			OutputDir: "dist",
+		},
+		Output: Output{
+			Formats: []string{output.FormatJSON},
+		},
+		Diff: Diff{
+			Enabled:  true,
+			Locale:   diff.LocaleJa,
+			Strategy: string(diff.StrategyConsecutive),
+		},
+		Lint: Lint{
+			Enabled: true,
+		},
+	}
+}
+
+// Load は設定ファイルを既定値に重ねて読み込み、環境変数による上書きを適用して検証します。
+// path が空の場合はカレントディレクトリの DefaultFile を探し、存在しなければ既定値を使います。
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err == nil {
+			path = DefaultFile
+		}
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("設定ファイル '%s' の読み込みに失敗しました: %w", path, err)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("設定ファイル '%s' の解析に失敗しました: %w", path, err)
+		}
+		cfg.resolvePaths(filepath.Dir(path))
+	}
+
+	if err := cfg.applyEnv(os.LookupEnv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("設定が不正です: %w", err)
+	}
+	return cfg, nil
+}
+
+// resolvePaths は設定ファイル内の相対パスを設定ファイルのディレクトリを基準に解決します。
+func (c *Config) resolvePaths(baseDir string) {
+	resolve := func(p string) string {
+		if p == "" || filepath.IsAbs(p) {
+			return p
+		}
+		return filepath.Join(baseDir, p)
+	}
+	c.Layout.WorkDir = resolve(c.Layout.WorkDir)
+	c.Layout.OutputDir = resolve(c.Layout.OutputDir)
+	c.Diff.Translations = resolve(c.Diff.Translations)
+	c.Diff.Rules = resolve(c.Diff.Rules)
+	c.Lint.Rules = resolve(c.Lint.Rules)
+	for i := range c.Sources {
+		c.Sources[i].Dir = resolve(c.Sources[i].Dir)
+	}
+}
+
+// applyEnv は OASDOC_ で始まる環境変数で設定を上書きします。
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	if v, ok := lookup(envPrefix + "WORK_DIR"); ok {
+		c.Layout.WorkDir = v
+	}
+	if v, ok := lookup(envPrefix + "OUTPUT_DIR"); ok {
+		c.Layout.OutputDir = v
+	}
+	if v, ok := lookup(envPrefix + "FORMATS"); ok {
+		c.Output.Formats = splitList(v)
+	}
+	if v, ok := lookup(envPrefix + "SITE_URL"); ok {
+		c.Output.SiteURL = v
+	}
+	if v, ok := lookup(envPrefix + "AUDIENCES"); ok {
+		c.Output.Audiences = splitList(v)
+	}
+	if v, ok := lookup(envPrefix + "LOCALE"); ok {
+		c.Diff.Locale = v
+	}
+	if v, ok := lookup(envPrefix + "DIFF_TRANSLATIONS"); ok {
+		c.Diff.Translations = v
+	}
+	if v, ok := lookup(envPrefix + "DIFF_RULES"); ok {
+		c.Diff.Rules = v
+	}
+	if v, ok := lookup(envPrefix + "DIFF_STRATEGY"); ok {
+		c.Diff.Strategy = v
+	}
+	if v, ok := lookup(envPrefix + "DIFF_WORKERS"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("環境変数 %sDIFF_WORKERS が数値ではありません: %s", envPrefix, v)
+		}
+		c.Diff.Workers = n
+	}
+	if v, ok := lookup(envPrefix + "LINT_RULES"); ok {
+		c.Lint.Rules = v
+	}
+	if v, ok := lookup(envPrefix + "LINT_FAIL_ON"); ok {
+		c.Lint.FailOn = v
+	}
+	if v, ok := lookup(envPrefix + "REPO_URL"); ok {
+		c.Sources = []Source{{Repo: v}}
+	}
+	if v, ok := lookup(envPrefix + "MAX_VERSIONS"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("環境変数 %sMAX_VERSIONS が数値ではありません: %s", envPrefix, v)
+		}
+		for i := range c.Sources {
+			c.Sources[i].MaxVersions = n
+		}
+	}
+	return nil
+}
+
+// Validate は設定値の整合性を検証し、見つかった全ての問題をまとめて返します。
+func (c *Config) Validate() error {
+	var errs []error
+	if c.Version != 1 {
+		errs = append(errs, fmt.Errorf("version: 未対応のバージョンです: %d", c.Version))
+	}
+	for i, source := range c.Sources {
+		if (source.Repo == "") == (source.Dir == "") {
+			errs = append(errs, fmt.Errorf("sources[%d]: repo と dir のどちらか一方を指定してください", i))
+		}
+		if source.MaxVersions < 0 {
+			errs = append(errs, fmt.Errorf("sources[%d].maxVersions: 0以上を指定してください", i))
+		}
+	}
+	for _, pattern := range append(append([]string{}, c.APIs.Include...), c.APIs.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("apis: 不正なパターンです: %s", pattern))
+		}
+	}
+	if c.Layout.WorkDir == "" {
+		errs = append(errs, errors.New("layout.workDir: 指定してください"))
+	}
+	if c.Layout.OutputDir == "" {
+		errs = append(errs, errors.New("layout.outputDir: 指定してください"))
+	}
+	if len(c.Output.Formats) == 0 {
+		errs = append(errs, errors.New("output.formats: 1つ以上指定してください"))
+	}
+	if err := output.Validate(c.Output.Formats); err != nil {
+		errs = append(errs, fmt.Errorf("output.formats: %w", err))
+	}
+	if c.Output.SiteURL != "" {
+		if u, err := url.Parse(c.Output.SiteURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("output.siteURL: http(s) の絶対URLを指定してください: %s", c.Output.SiteURL))
+		}
+	}
+	if err := ValidateAudiences(c.Output.Audiences); err != nil {
+		errs = append(errs, fmt.Errorf("output.audiences: %w", err))
+	}
+	if c.Diff.Locale == "" {
+		errs = append(errs, errors.New("diff.locale: 指定してください"))
+	}
+	if _, err := diff.ParseStrategy(c.Diff.Strategy); err != nil {
+		errs = append(errs, fmt.Errorf("diff.strategy: %w", err))
+	}
+	if c.Diff.Workers < 0 {
+		errs = append(errs, errors.New("diff.workers: 0以上を指定してください"))
+	}
+	if c.Lint.FailOn != "" {
+		if _, err := downloader.ParseLevel(c.Lint.FailOn); err != nil {
+			errs = append(errs, fmt.Errorf("lint.failOn: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateAudiences は対象者の名前が出力先のディレクトリ名として使えて、重複していないかを検証します。
+func ValidateAudiences(audiences []string) error {
+	seen := make(map[string]bool)
+	for _, audience := range audiences {
+		if audience == "" || audience == "." || audience == ".." || strings.ContainsAny(audience, `/\`) {
+			return fmt.Errorf("ディレクトリ名に使えない対象者です: %q", audience)
+		}
+		if seen[audience] {
+			return fmt.Errorf("対象者が重複しています: %s", audience)
+		}
+		seen[audience] = true
+	}
+	return nil
+}
+
+// IncludesAPI はAPI名が include/exclude のルールで対象になるかを返します。
+func (f APIFilter) IncludesAPI(name string) bool {
+	for _, pattern := range f.Exclude {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, pattern := range f.Include {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// InputDirs は仕様ファイルを読み込む入力ディレクトリを返します。
+// sources[].dir の各ディレクトリに加え、リポジトリからダウンロードする場合と取得元の指定がない場合は layout.workDir を先頭に含めます。
+func (c *Config) InputDirs() []string {
+	var dirs []string
+	download := len(c.Sources) == 0
+	for _, source := range c.Sources {
+		if source.Repo != "" {
+			download = true
+		} else {
+			dirs = append(dirs, source.Dir)
+		}
+	}
+	if download {
+		dirs = append([]string{c.Layout.WorkDir}, dirs...)
+	}
+	return dirs
+}
+
+// MaxVersionsOrDefault は収集するバージョン数を返します。指定されていなければ既定値の5を返します。
+func (s Source) MaxVersionsOrDefault() int {
+	if s.MaxVersions == 0 {
+		return 5
+	}
+	return s.MaxVersions
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// scaffold は init コマンドで作成する設定ファイルの雛形です。
+const scaffold = `# openapi-static-document-generator の設定ファイル
+# コマンドラインフラグ > 環境変数 (OASDOC_*) > この設定ファイル > 既定値 の順に優先されます。
+version: 1
+
+# 仕様ファイルの取得元。repo (Gitリポジトリ) と dir (ローカルディレクトリ) のどちらかを指定します。
+# dir は <dir>/<API名>/<バージョン>/openapi.yaml の構成である必要があります。
+sources:
+  - repo: https://github.com/example/api-specs.git
+    maxVersions: 5
+  # - dir: ./specs
+
+# 対象とするAPI名のグロブパターン。exclude が include より優先されます。
+apis:
+  include: []
+  exclude: []
+
+layout:
+  # ダウンロード先かつ差分計算・生成の入力ディレクトリ (OASDOC_WORK_DIR)
+  workDir: downloaded_apis
+  # 生成物の出力先ディレクトリ (OASDOC_OUTPUT_DIR)
+  outputDir: dist
+
+output:
+  # json, html, feed, markdown, postman, insomnia, http (OASDOC_FORMATS)
+  formats:
+    - json
+  failOnInvalidExamples: false
+  # 公開するサイトのURL。feed のリンクを絶対URLにするために使います (OASDOC_SITE_URL)
+  # siteURL: https://api-docs.example.com
+  # 対象者ごとに絞り込んだサイトを <outputDir>/<対象者> に出力します (OASDOC_AUDIENCES)
+  # x-internal: true の要素は internal 向けにだけ、x-audience: [partner, public] の要素は列挙した対象者向けにだけ出力します。
+  # internal 向けには全ての要素を出力します。
+  # audiences: [internal, partner, public]
+
+diff:
+  enabled: true
+  # 差分メッセージの言語。ja, en のほか翻訳ファイルで追加したロケールを指定できます (OASDOC_LOCALE)
+  locale: ja
+  # 組み込みの翻訳を上書き・追加する翻訳ファイル (OASDOC_DIFF_TRANSLATIONS)
+  # ロケール → メッセージキー → 書式 の YAML です。例:
+  #   en:
+  #     api-path-removed-without-deprecation: "API path removed without deprecation"
+  # translations: translations.yaml
+  # 意図的な変更を承認済みにする無視ルールと重要度の上書き (OASDOC_DIFF_RULES)
+  #   ignore:
+  #     - id: api-path-removed-without-deprecation
+  #       path: /pets/{petId}
+  #       versions: ">=2.0.0"
+  #       reason: 2.0.0 で /animals に移行済み
+  #   severity:
+  #     response-optional-property-added: WARN
+  # rules: oasdoc-rules.yaml
+  # 差分を計算するバージョンの組み合わせ (OASDOC_DIFF_STRATEGY)
+  #   consecutive: 隣り合うバージョン同士 / latest: 最新バージョンとの比較
+  #   previous-major: 1つ前のメジャーバージョンの最新版との比較 / full: 全ての組み合わせ
+  strategy: consecutive
+  # 同時に実行する差分計算の数。0 なら CPU 数 (OASDOC_DIFF_WORKERS)
+  workers: 0
+
+lint:
+  # 生成時に仕様書のスタイルを検査し、結果をバージョンごとにサイトのデータに含めます
+  enabled: true
+  # ルールのレベルの上書きとルールの設定 (OASDOC_LINT_RULES)。レベルは ERR, WARN, INFO, off です。例:
+  #   rules:
+  #     operation-tags: off
+  #     parameter-description: ERR
+  #   pagination:
+  #     parameters: [limit, cursor]
+  # rules: oasdoc-lint.yaml
+  # 最新バージョンにこのレベル以上の問題があれば生成を失敗させます。空なら失敗させません (OASDOC_LINT_FAIL_ON)
+  # failOn: ERR
+`
+
+// WriteScaffold は設定ファイルの雛形を path に書き出します。force が false の場合は既存のファイルを上書きしません。
+func WriteScaffold(path string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("'%s' は既に存在します", path)
+		}
+	}
+	return os.WriteFile(path, []byte(scaffold), 0644)
+}
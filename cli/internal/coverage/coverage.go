@@ -0,0 +1,140 @@
+package coverage
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"math"
+	"strings"
+)
+
+// Metric は対象の数のうち、ドキュメントが揃っているものの数です。
+type Metric struct {
+	Covered int `json:"covered"`
+	Total   int `json:"total"`
+}
+
+// Percent は充足率を 0 〜 100 で返します。対象がない場合は ok=false を返します。
+func (m Metric) Percent() (percent float64, ok bool) {
+	if m.Total == 0 {
+		return 0, false
+	}
+	return float64(m.Covered) * 100 / float64(m.Total), true
+}
+
+func (m *Metric) add(covered bool) {
+	m.Total++
+	if covered {
+		m.Covered++
+	}
+}
+
+// Coverage は1つのバージョンの仕様書のドキュメントの充実度です。
+type Coverage struct {
+	OperationSummaries    Metric `json:"operationSummaries"`
+	OperationDescriptions Metric `json:"operationDescriptions"`
+	TaggedOperations      Metric `json:"taggedOperations"`
+	ParameterDescriptions Metric `json:"parameterDescriptions"`
+	SchemaExamples        Metric `json:"schemaExamples"`
+	ResponseExamples      Metric `json:"responseExamples"`
+	// Score は対象がある指標の充足率の平均を小数第1位で丸めた値です。
+	Score float64 `json:"score"`
+}
+
+// NamedMetric は表示名のついた指標です。
+type NamedMetric struct {
+	Key    string
+	Label  string
+	Metric Metric
+}
+
+// Metrics は指標を表示する順に返します。
+func (c Coverage) Metrics() []NamedMetric {
+	return []NamedMetric{
+		{Key: "operationSummaries", Label: "summary のあるオペレーション", Metric: c.OperationSummaries},
+		{Key: "operationDescriptions", Label: "description のあるオペレーション", Metric: c.OperationDescriptions},
+		{Key: "taggedOperations", Label: "タグのあるオペレーション", Metric: c.TaggedOperations},
+		{Key: "parameterDescriptions", Label: "説明のあるパラメータ", Metric: c.ParameterDescriptions},
+		{Key: "schemaExamples", Label: "例のあるスキーマ", Metric: c.SchemaExamples},
+		{Key: "responseExamples", Label: "例のあるレスポンス", Metric: c.ResponseExamples},
+	}
+}
+
+// Compute は仕様書のドキュメントの充実度を計算します。
+func Compute(doc *openapi3.T) Coverage {
+	var c Coverage
+	for _, op := range specutil.Operations(doc) {
+		c.OperationSummaries.add(strings.TrimSpace(op.Operation.Summary) != "")
+		c.OperationDescriptions.add(strings.TrimSpace(op.Operation.Description) != "")
+		c.TaggedOperations.add(len(op.Operation.Tags) > 0)
+		for _, ref := range op.Parameters() {
+			c.ParameterDescriptions.add(strings.TrimSpace(ref.Value.Description) != "")
+		}
+		if op.Operation.Responses == nil {
+			continue
+		}
+		for _, ref := range op.Operation.Responses.Map() {
+			// 本文のないレスポンス (204 など) は例を必要としない
+			if ref == nil || ref.Value == nil || len(ref.Value.Content) == 0 {
+				continue
+			}
+			c.ResponseExamples.add(contentHasExample(ref.Value.Content))
+		}
+	}
+	if doc != nil && doc.Components != nil {
+		for _, name := range specutil.SchemaNames(doc) {
+			ref := doc.Components.Schemas[name]
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			c.SchemaExamples.add(schemaHasExample(ref.Value))
+		}
+	}
+	c.Score = score(c)
+	return c
+}
+
+// contentHasExample はいずれかのメディアタイプに example、examples、またはスキーマの example があるかを返します。
+func contentHasExample(content openapi3.Content) bool {
+	for _, mediaType := range content {
+		if mediaType == nil {
+			continue
+		}
+		if mediaType.Example != nil || len(mediaType.Examples) > 0 {
+			return true
+		}
+		if mediaType.Schema != nil && mediaType.Schema.Value != nil && schemaHasExample(mediaType.Schema.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaHasExample はスキーマ自体に example があるか、オブジェクトの全てのプロパティに example があるかを返します。
+func schemaHasExample(schema *openapi3.Schema) bool {
+	if schema.Example != nil {
+		return true
+	}
+	if len(schema.Properties) == 0 {
+		return false
+	}
+	for _, prop := range schema.Properties {
+		if prop == nil || prop.Value == nil || prop.Value.Example == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func score(c Coverage) float64 {
+	sum, count := 0.0, 0
+	for _, m := range c.Metrics() {
+		if percent, ok := m.Metric.Percent(); ok {
+			sum += percent
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return math.Round(sum/float64(count)*10) / 10
+}
@@ -0,0 +1,148 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// レポートの出力フォーマット
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// Formats は Write が対応している出力フォーマットです。
+var Formats = []string{FormatText, FormatMarkdown, FormatJSON}
+
+// APIReport は1つの API のバージョンごとのドキュメントの充実度です。
+type APIReport struct {
+	Name string `json:"name"`
+	// Versions は古いバージョンから順に並びます
+	Versions []VersionReport `json:"versions"`
+}
+
+// VersionReport は1つのバージョンのドキュメントの充実度です。
+type VersionReport struct {
+	Version  string    `json:"version"`
+	Date     time.Time `json:"date"`
+	Coverage Coverage  `json:"coverage"`
+}
+
+// ValidateFormat は出力フォーマットが対応しているかを検証します。
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応の出力フォーマットです: %s (%s)", format, strings.Join(Formats, ", "))
+}
+
+// Write は API ごとの充実度の推移を format で書き出します。
+func Write(w io.Writer, reports []APIReport, format string) error {
+	switch format {
+	case FormatText:
+		return writeText(w, reports)
+	case FormatMarkdown:
+		writeMarkdown(w, reports)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(reports)
+	}
+	return ValidateFormat(format)
+}
+
+// writeText はバージョンを列、指標を行にした表を書き出します。
+// 日本語の表示幅で列がずれないよう、指標名は最後の列に置きます。
+func writeText(w io.Writer, reports []APIReport) error {
+	for i, report := range reports {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, report.Name)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		var header []string
+		for _, v := range report.Versions {
+			header = append(header, v.Version)
+		}
+		fmt.Fprintf(tw, "  %s\t指標\n", strings.Join(header, "\t"))
+		for _, row := range rows(report) {
+			fmt.Fprintf(tw, "  %s\t%s\n", strings.Join(row.cells, "\t"), row.label)
+		}
+		if err := tw.Flush(); err != nil {
+			return fmt.Errorf("表の書き出しに失敗しました: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeMarkdown(w io.Writer, reports []APIReport) {
+	fmt.Fprintln(w, "# ドキュメントの充実度")
+	for _, report := range reports {
+		fmt.Fprintf(w, "\n## %s\n\n", report.Name)
+		header := []string{"指標"}
+		for _, v := range report.Versions {
+			header = append(header, v.Version)
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "|%s\n", strings.Repeat(" --- |", len(header)))
+		for _, row := range rows(report) {
+			fmt.Fprintf(w, "| %s | %s |\n", row.label, strings.Join(row.cells, " | "))
+		}
+	}
+}
+
+type row struct {
+	label string
+	cells []string
+}
+
+// rows は指標ごとに、各バージョンの充足率と1つ前のバージョンからの増減を並べた行を返します。最後の行はスコアです。
+func rows(report APIReport) []row {
+	var result []row
+	for i, m := range (Coverage{}).Metrics() {
+		r := row{label: m.Label}
+		previous, hasPrevious := 0.0, false
+		for _, v := range report.Versions {
+			metric := v.Coverage.Metrics()[i].Metric
+			percent, ok := metric.Percent()
+			if !ok {
+				r.cells = append(r.cells, "-")
+				hasPrevious = false
+				continue
+			}
+			r.cells = append(r.cells, fmt.Sprintf("%.1f%% (%d/%d)%s", percent, metric.Covered, metric.Total, trend(percent, previous, hasPrevious)))
+			previous, hasPrevious = percent, true
+		}
+		result = append(result, r)
+	}
+
+	score := row{label: "スコア"}
+	for j, v := range report.Versions {
+		cell := fmt.Sprintf("%.1f", v.Coverage.Score)
+		if j > 0 {
+			cell += trend(v.Coverage.Score, report.Versions[j-1].Coverage.Score, true)
+		}
+		score.cells = append(score.cells, cell)
+	}
+	return append(result, score)
+}
+
+// trend は1つ前のバージョンからの増減を " (+5)" の形式で返します。変化がなければ空文字を返します。
+func trend(current float64, previous float64, hasPrevious bool) string {
+	if !hasPrevious {
+		return ""
+	}
+	delta := current - previous
+	if delta > -0.05 && delta < 0.05 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.1f)", delta)
+}
@@ -0,0 +1,171 @@
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"io"
+	"strings"
+	"time"
+)
+
+// レポートの出力フォーマット
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// Formats は Write が対応している出力フォーマットです。
+var Formats = []string{FormatText, FormatMarkdown, FormatJSON}
+
+// Report はある時点での API ごとの非推奨のオペレーションの一覧です。
+type Report struct {
+	// Date は廃止予定日を過ぎたかを判定した日時
+	Date time.Time   `json:"date"`
+	APIs []APIReport `json:"apis"`
+}
+
+// APIReport は1つの API の非推奨のオペレーションです。
+type APIReport struct {
+	Name    string  `json:"name"`
+	Entries []Entry `json:"deprecations"`
+}
+
+// Entry は非推奨のオペレーションと、廃止予定日を過ぎても残っているかです。
+type Entry struct {
+	generator.Deprecation
+	Overdue bool `json:"overdue"`
+}
+
+// NewReport は siteData のうち apiNames の API (空なら全て) について、now の時点のレポートを作ります。
+// 非推奨のオペレーションがない API は含みません。
+func NewReport(siteData *generator.SiteData, apiNames []string, now time.Time) *Report {
+	report := &Report{Date: now, APIs: []APIReport{}}
+	for _, api := range siteData.APIs {
+		if len(apiNames) > 0 && !contains(apiNames, api.Name) {
+			continue
+		}
+		if len(api.Deprecations) == 0 {
+			continue
+		}
+		r := APIReport{Name: api.Name}
+		for _, d := range api.Deprecations {
+			r.Entries = append(r.Entries, Entry{Deprecation: d, Overdue: d.Overdue(now)})
+		}
+		report.APIs = append(report.APIs, r)
+	}
+	return report
+}
+
+// Overdue は廃止予定日を過ぎても残っているオペレーションの数を返します。
+func (r *Report) Overdue() int {
+	count := 0
+	for _, api := range r.APIs {
+		for _, e := range api.Entries {
+			if e.Overdue {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// ValidateFormat は出力フォーマットが対応しているかを検証します。
+func ValidateFormat(format string) error {
+	if contains(Formats, format) {
+		return nil
+	}
+	return fmt.Errorf("未対応の出力フォーマットです: %s (%s)", format, strings.Join(Formats, ", "))
+}
+
+// Write はレポートを format で書き出します。
+func Write(w io.Writer, r *Report, format string) error {
+	switch format {
+	case FormatText:
+		writeText(w, r)
+		return nil
+	case FormatMarkdown:
+		writeMarkdown(w, r)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	}
+	return ValidateFormat(format)
+}
+
+func writeText(w io.Writer, r *Report) {
+	if len(r.APIs) == 0 {
+		fmt.Fprintln(w, "非推奨のオペレーションはありません。")
+		return
+	}
+	for _, api := range r.APIs {
+		fmt.Fprintf(w, "%s (%d件)\n", api.Name, len(api.Entries))
+		for _, e := range api.Entries {
+			fmt.Fprintf(w, "  %s %s\n", e.Method, e.Path)
+			fmt.Fprintf(w, "    非推奨化: %s\n", deprecatedSince(e.Deprecation))
+			fmt.Fprintf(w, "    廃止予定日: %s\n", sunset(e.Deprecation))
+			if e.StabilityLevel != "" {
+				fmt.Fprintf(w, "    安定度: %s\n", e.StabilityLevel)
+			}
+			fmt.Fprintf(w, "    状態: %s\n", status(e))
+		}
+	}
+	fmt.Fprintf(w, "\n廃止予定日を過ぎても残っているオペレーション: %d件 (%s 時点)\n", r.Overdue(), r.Date.Format("2006-01-02"))
+}
+
+func writeMarkdown(w io.Writer, r *Report) {
+	fmt.Fprintf(w, "# 非推奨のオペレーション (%s 時点)\n", r.Date.Format("2006-01-02"))
+	if len(r.APIs) == 0 {
+		fmt.Fprintln(w, "\n非推奨のオペレーションはありません。")
+		return
+	}
+	for _, api := range r.APIs {
+		fmt.Fprintf(w, "\n## %s\n\n", api.Name)
+		fmt.Fprintln(w, "| オペレーション | 非推奨化 | 廃止予定日 | 安定度 | 状態 |")
+		fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+		for _, e := range api.Entries {
+			fmt.Fprintf(w, "| `%s %s` | %s | %s | %s | %s |\n", e.Method, e.Path, deprecatedSince(e.Deprecation), sunset(e.Deprecation), e.StabilityLevel, status(e))
+		}
+	}
+}
+
+func deprecatedSince(d generator.Deprecation) string {
+	switch {
+	case d.DeprecatedSince == "":
+		return "-"
+	case d.DeprecatedDate != nil && !d.DeprecatedDate.IsZero():
+		return fmt.Sprintf("%s (%s)", d.DeprecatedSince, d.DeprecatedDate.Format("2006-01-02"))
+	default:
+		return d.DeprecatedSince
+	}
+}
+
+func sunset(d generator.Deprecation) string {
+	if d.Sunset == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s)", d.Sunset, d.SunsetSource)
+}
+
+func status(e Entry) string {
+	switch {
+	case e.Status == generator.DeprecationRemoved:
+		return fmt.Sprintf("%s で削除済み", e.RemovedIn)
+	case e.Overdue:
+		return fmt.Sprintf("⚠ 廃止予定日を過ぎても %s に残っています", e.LastVersion)
+	default:
+		return fmt.Sprintf("%s に残っています", e.LastVersion)
+	}
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package diff
+
+import (
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"slices"
+)
+
+// docKeywords は構造的な差分で説明文や例などドキュメントの項目とみなす項目名です。
+var docKeywords = []string{"description", "summary", "example", "examples", "title"}
+
+// RequiredBump は変更の内容から必要なバージョンの上げ方を求めます。
+// 互換性を損なう変更 (downloader.BreakingLevel 以上) があれば major、それ以外の変更か、構造的な差分にドキュメント以外の項目の変更があれば minor、
+// 構造的な差分が説明文や例などドキュメントの項目の変更だけであれば patch を返します。承認済みの変更も数えます。
+func RequiredBump(changes downloader.Diff, structural downloader.StructuralDiff) semver.Bump {
+	for _, change := range changes {
+		if change.IsBreaking() {
+			return semver.BumpMajor
+		}
+	}
+	if len(changes) > 0 {
+		return semver.BumpMinor
+	}
+
+	bump := semver.BumpNone
+	for _, field := range fieldChangesOf(structural) {
+		if !IsDocField(field) {
+			return semver.BumpMinor
+		}
+		bump = semver.BumpPatch
+	}
+	return bump
+}
+
+// fieldChangesOf は構造的な差分の全てのオペレーションとスキーマの項目の変更を返します。
+func fieldChangesOf(structural downloader.StructuralDiff) []downloader.FieldChange {
+	var fields []downloader.FieldChange
+	for _, methods := range structural.Operations {
+		for _, changes := range methods {
+			fields = append(fields, changes...)
+		}
+	}
+	for _, changes := range structural.Schemas {
+		fields = append(fields, changes...)
+	}
+	return fields
+}
+
+// IsDocField は構造的な差分の項目の変更が、説明文や例などドキュメントの項目の値の変更かを返します。
+// 経路の最後の項目名が docKeywords のいずれかと一致する modified の変更だけを対象とします。
+func IsDocField(change downloader.FieldChange) bool {
+	if change.Kind != KindModified || len(change.Path) == 0 {
+		return false
+	}
+	return slices.Contains(docKeywords, change.Path[len(change.Path)-1])
+}
+
+// VersionCheck は2つのバージョンの間の変更に対して、バージョンの上げ方が十分かを検証した結果です。
+type VersionCheck struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Required semver.Bump `json:"required"`
+	// Actual は実際のバージョンの上げ方です。バージョンとして解釈できない場合は none になります。
+	Actual semver.Bump `json:"actual"`
+	// OK は実際の上げ方が必要な上げ方以上であるかを表します。
+	OK bool `json:"ok"`
+}
+
+// CheckVersion は from から to への変更 changes と構造的な差分 structural に対して、バージョンの上げ方が十分かを検証します。
+// from が 0.y.z の場合は、互換性を損なう変更でも minor を上げていれば十分とみなします。
+// どちらかがバージョンとして解釈できない場合は ok=false を返します。
+func CheckVersion(from string, to string, changes downloader.Diff, structural downloader.StructuralDiff) (check VersionCheck, ok bool) {
+	actual, ok := semver.Difference(from, to)
+	if !ok {
+		return VersionCheck{}, false
+	}
+	check = VersionCheck{From: from, To: to, Required: RequiredBump(changes, structural), Actual: actual}
+
+	required := check.Required
+	if required == semver.BumpMajor && semver.IsInitialDevelopment(from) {
+		required = semver.BumpMinor
+	}
+	check.OK = actual >= required
+	return check, true
+}
@@ -0,0 +1,64 @@
+package diff
+
+import (
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"testing"
+)
+
+func TestRequiredBump(t *testing.T) {
+	docOnly := downloader.StructuralDiff{
+		Operations: map[string]map[string][]downloader.FieldChange{
+			"/pets": {"GET": {{Path: []string{"description"}, Kind: KindModified, From: "a", To: "b"}}},
+		},
+	}
+	schemaChanged := downloader.StructuralDiff{
+		Schemas: map[string][]downloader.FieldChange{
+			"Pet": {{Path: []string{"properties", "name", "maxLength"}, Kind: KindModified, From: 10, To: 20}},
+		},
+	}
+	tests := []struct {
+		name       string
+		changes    downloader.Diff
+		structural downloader.StructuralDiff
+		want       semver.Bump
+	}{
+		{"変更なし", nil, downloader.StructuralDiff{}, semver.BumpNone},
+		{"互換性を損なう変更", downloader.Diff{{Id: "api-path-removed-without-deprecation", Level: downloader.LevelErr}}, docOnly, semver.BumpMajor},
+		{"承認済みでも数える", downloader.Diff{{Id: "api-path-removed-without-deprecation", Level: downloader.LevelErr, Acknowledged: true}}, docOnly, semver.BumpMajor},
+		{"WARN も互換性を損なう変更", downloader.Diff{{Id: "response-property-became-optional", Level: downloader.LevelWarn}}, downloader.StructuralDiff{}, semver.BumpMajor},
+		{"互換性を損なわない変更", downloader.Diff{{Id: "endpoint-added", Level: downloader.LevelInfo}}, docOnly, semver.BumpMinor},
+		{"ドキュメントの項目だけの変更", nil, docOnly, semver.BumpPatch},
+		{"ドキュメント以外の項目の変更", nil, schemaChanged, semver.BumpMinor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequiredBump(tt.changes, tt.structural); got != tt.want {
+				t.Errorf("RequiredBump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDocField(t *testing.T) {
+	tests := []struct {
+		name   string
+		change downloader.FieldChange
+		want   bool
+	}{
+		{"説明の変更", downloader.FieldChange{Path: []string{"parameters", "query", "limit", "description"}, Kind: KindModified}, true},
+		{"例の変更", downloader.FieldChange{Path: []string{"properties", "name", "example"}, Kind: KindModified}, true},
+		{"前方一致しても別の項目", downloader.FieldChange{Path: []string{"properties", "titleFormat"}, Kind: KindModified}, false},
+		{"ドキュメントの項目名のプロパティの追加", downloader.FieldChange{Path: []string{"properties", "description"}, Kind: KindAdded}, false},
+		{"ドキュメントの項目名のプロパティの削除", downloader.FieldChange{Path: []string{"properties", "title"}, Kind: KindDeleted}, false},
+		{"ドキュメント以外の項目", downloader.FieldChange{Path: []string{"maxLength"}, Kind: KindModified}, false},
+		{"経路が空", downloader.FieldChange{Path: []string{}, Kind: KindModified}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDocField(tt.change); got != tt.want {
+				t.Errorf("IsDocField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
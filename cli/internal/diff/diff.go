@@ -2,42 +2,65 @@ package diff
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/oasdiff/oasdiff/checker"
-	localizations2 "github.com/oasdiff/oasdiff/checker/localizations"
 	"github.com/oasdiff/oasdiff/diff"
 	"github.com/oasdiff/oasdiff/formatters"
 	"github.com/oasdiff/oasdiff/load"
-	"log"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
 	"os"
 	"path/filepath"
+	"sort"
+	"sync"
 )
 
-var localizer = localizations2.Localizer{
-	Locale:         "ja",
-	FallbackLocale: "ja",
-	Localizations:  localizations,
-}
-
-func GetAllDiff(path string) {
+// GetAllDiff は path 以下の API ごとに差分を計算し、各バージョンの diff.json と structural-diff.json に書き出します。
+// 一部の API で失敗しても残りの API の計算は続け、失敗した API のエラーをまとめて返します。
+func GetAllDiff(path string, opts Options) error {
 	name, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("入力ディレクトリのパスの解決に失敗しました: %w", err)
+	}
 	dir, err := os.ReadDir(name)
 	if err != nil {
-		log.Fatalf("Error reading directory: %v", err)
+		return fmt.Errorf("入力ディレクトリの読み込みに失敗しました: %w", err)
 	}
 
-	for _, entry := range dir {
+	// API ごとの読み込みと、全 API で共有する差分計算の両方を同じ並列数で制限する
+	apis := newLimiter(opts.workers())
+	pairs := newLimiter(opts.workers())
+	errs := make([]error, len(dir))
+	var wg sync.WaitGroup
+	for i, entry := range dir {
+		if !entry.IsDir() {
+			continue
+		}
 		rel := filepath.Join(name, entry.Name())
-		GetApiDiff(rel)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			apis.acquire()
+			defer apis.release()
+			errs[i] = getApiDiff(rel, opts, pairs)
+		}()
 	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// GetApiDiff は1つの API のディレクトリの差分を計算し、各バージョンのファイルに書き出します。
+func GetApiDiff(path string, opts Options) error {
+	return getApiDiff(path, opts, newLimiter(opts.workers()))
 }
 
-func GetApiDiff(path string) {
+func getApiDiff(path string, opts Options, pairs limiter) error {
+	apiName := filepath.Base(path)
 	dir, err := os.ReadDir(path)
 	if err != nil {
-		log.Fatalf("Error reading directory: %v", err)
-		return
+		return fmt.Errorf("%s のディレクトリの読み込みに失敗しました: %w", apiName, err)
 	}
 
 	loader := openapi3.NewLoader()
@@ -53,85 +76,253 @@ func GetApiDiff(path string) {
 		rel := filepath.Join(path, version)
 		readDir, err := os.ReadDir(rel)
 		if err != nil {
-			log.Fatalf("Error reading directory: %v", err)
-			return
+			return fmt.Errorf("%s %s のディレクトリの読み込みに失敗しました: %w", apiName, version, err)
 		}
 
 		for _, dirEntry := range readDir {
-			if dirEntry.Name() == "info.json" || dirEntry.Name() == "diff.json" {
+			if dirEntry.Name() == "info.json" || dirEntry.Name() == "diff.json" || dirEntry.Name() == "structural-diff.json" {
 				continue
 			}
 			specPath := filepath.Join(rel, dirEntry.Name())
 
 			info, err := load.NewSpecInfo(loader, load.NewSource(specPath))
 			if err != nil {
-				log.Fatalf("Error loading spec: %v", err)
-				return
+				return fmt.Errorf("%s の読み込みに失敗しました: %w", specPath, err)
 			}
 			versions[version] = *info
 		}
 	}
 
-	for s, info := range versions {
-		var jsons = make(map[string]interface{})
-		for s2, specInfo := range versions {
-			if s == s2 {
-				continue
-			}
-			getDiff, err := GetDiff(&info, &specInfo)
-			if err != nil {
-				log.Fatalf("Error getting diff: %v", err)
-			}
-			var data interface{}
-			err = json.Unmarshal(getDiff, &data)
-			if err != nil {
-				log.Fatalf("Error unmarshalling diff: %v", err)
-			}
-			jsons[s2] = data
+	diffs, structural, err := computeDiffs(apiName, versions, opts, pairs)
+	if err != nil {
+		return fmt.Errorf("%s の差分の計算に失敗しました: %w", apiName, err)
+	}
+	for s, d := range diffs {
+		if err := writeDiffFile(filepath.Join(path, s, "diff.json"), d); err != nil {
+			return err
 		}
-		marshal, err := json.Marshal(jsons)
-		if err != nil {
-			return
+		if err := writeStructuralDiffFile(filepath.Join(path, s, "structural-diff.json"), structural[s]); err != nil {
+			return err
 		}
-		os.WriteFile(filepath.Join(path, s, "diff.json"), marshal, os.ModePerm)
+	}
+	return nil
+}
+
+// ApplyDiffs は解析済みのドキュメントをそのまま使って差分を計算し、
+// 各バージョンの diff.json と structural-diff.json に書き出すとともに doc.Diffs と doc.StructuralDiffs に反映します。
+func ApplyDiffs(docs []*parser.APIDocument, opts Options) error {
+	apis := make(map[string]map[string]*parser.APIDocument)
+	for _, doc := range docs {
+		if apis[doc.APIName] == nil {
+			apis[doc.APIName] = make(map[string]*parser.APIDocument)
+		}
+		apis[doc.APIName][doc.Version] = doc
+	}
 
+	apiNames := make([]string, 0, len(apis))
+	for apiName := range apis {
+		apiNames = append(apiNames, apiName)
 	}
+	sort.Strings(apiNames)
+
+	// SpecInfo は解析済みの仕様を読み取り専用で共有し、差分計算だけを並列に行う
+	pairs := newLimiter(opts.workers())
+	errs := make([]error, len(apiNames))
+	var wg sync.WaitGroup
+	for i, apiName := range apiNames {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = applyAPIDiffs(apiName, apis[apiName], opts, pairs)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-func GetDiff(spec1 *load.SpecInfo, spec2 *load.SpecInfo) ([]byte, error) {
-	diffConfig := diff.NewConfig()
+// applyAPIDiffs は1つの API の差分を計算し、各バージョンのファイルとドキュメントに反映します。
+func applyAPIDiffs(apiName string, apiDocs map[string]*parser.APIDocument, opts Options, pairs limiter) error {
+	versions := make(map[string]load.SpecInfo)
+	for version, doc := range apiDocs {
+		versions[version] = specInfoFromDoc(doc)
+	}
 
-	diff, sourcesMap, err := diff.GetWithOperationsSourcesMap(diffConfig, spec1, spec2)
+	diffs, structural, err := computeDiffs(apiName, versions, opts, pairs)
 	if err != nil {
-		return []byte{}, err
+		return fmt.Errorf("%s の差分の計算に失敗しました: %w", apiName, err)
+	}
+	for version, d := range diffs {
+		doc := apiDocs[version]
+		if err := writeDiffFile(filepath.Join(filepath.Dir(doc.Path), "diff.json"), d); err != nil {
+			return err
+		}
+		if err := writeStructuralDiffFile(filepath.Join(filepath.Dir(doc.Path), "structural-diff.json"), structural[version]); err != nil {
+			return err
+		}
+		doc.Diffs = d
+		doc.StructuralDiffs = structural[version]
 	}
+	return nil
+}
 
-	checkConfig := checker.NewConfig(checker.GetAllChecks())
+// writeDiffFile は差分を diff.json として書き出します。
+func writeDiffFile(path string, diffs downloader.Diffs) error {
+	marshal, err := json.Marshal(diffs)
+	if err != nil {
+		return fmt.Errorf("差分のエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, marshal, os.ModePerm); err != nil {
+		return fmt.Errorf("diff.json の書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
 
-	changes := checker.CheckBackwardCompatibilityUntilLevel(checkConfig, diff, sourcesMap, checker.INFO)
+// writeStructuralDiffFile は構造的な差分を structural-diff.json として書き出します。
+func writeStructuralDiffFile(path string, diffs downloader.StructuralDiffs) error {
+	marshal, err := json.Marshal(diffs)
+	if err != nil {
+		return fmt.Errorf("構造的な差分のエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, marshal, os.ModePerm); err != nil {
+		return fmt.Errorf("structural-diff.json の書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
 
-	//formatter, err := formatters.Lookup("json", formatters.FormatterOpts{})
+// specInfoFromDoc は解析済みのドキュメントを再読み込みせずに oasdiff の SpecInfo に変換します。
+func specInfoFromDoc(doc *parser.APIDocument) load.SpecInfo {
+	info := load.SpecInfo{Url: doc.Path, Spec: doc.Doc}
+	if doc.Doc.Info != nil {
+		info.Version = doc.Doc.Info.Version
+	}
+	return info
+}
 
-	formatter := formatters.JSONFormatter{
-		Localizer: func(key string, args ...interface{}) string {
-			k := "messages." + key
-			pattern := localizer.Get(k)
+// SpecVersion はドキュメントの info.version を返します。info がなければディレクトリ名のバージョンを返します。
+func SpecVersion(doc *parser.APIDocument) string {
+	if doc.Doc != nil && doc.Doc.Info != nil && doc.Doc.Info.Version != "" {
+		return doc.Doc.Info.Version
+	}
+	return doc.Version
+}
 
-			if pattern == k {
-				return key
+// computeDiffs は戦略に従って選んだバージョンの組について差分を計算し、
+// バージョンごとに diff.json と structural-diff.json に書き出す差分を返します。
+// 組ごとの計算は pairs の範囲で並列に行いますが、結果は逐次実行と同じ内容になります。
+func computeDiffs(apiName string, versions map[string]load.SpecInfo, opts Options, pairs limiter) (map[string]downloader.Diffs, map[string]downloader.StructuralDiffs, error) {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+
+	ps := opts.Strategy.pairs(names)
+	localizer := opts.localizer()
+	results := make([]downloader.Diff, len(ps))
+	structural := make([]downloader.StructuralDiff, len(ps))
+	errs := make([]error, len(ps))
+	var wg sync.WaitGroup
+	for i, p := range ps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pairs.acquire()
+			defer pairs.release()
+			base, revision := versions[p.base], versions[p.revision]
+			results[i], structural[i], errs[i] = compare(&base, &revision, localizer)
+			if errs[i] == nil {
+				results[i] = opts.Rules.Apply(results[i], base.Version, revision.Version)
+				opts.logf("%s: %s → %s %d件の変更", apiName, p.base, p.revision, len(results[i]))
 			}
-			fmt.Printf(pattern, args...)
-			fmt.Println()
-			return fmt.Sprintf(pattern, args...)
-		},
+		}()
 	}
+	wg.Wait()
 
-	changelog, err := formatter.RenderChangelog(changes, formatters.RenderOpts{}, "", "")
+	all := make(map[string]downloader.Diffs)
+	allStructural := make(map[string]downloader.StructuralDiffs)
+	for name := range versions {
+		all[name] = downloader.Diffs{}
+		allStructural[name] = downloader.StructuralDiffs{}
+	}
+	for i, p := range ps {
+		// 逐次実行と同じく、最初に失敗した組のエラーを返す
+		if errs[i] != nil {
+			return nil, nil, errs[i]
+		}
+		all[p.base][p.revision] = results[i]
+		allStructural[p.base][p.revision] = structural[i]
+	}
+	return all, allStructural, nil
+}
+
+// GetDiff は日本語のメッセージで2つの仕様の差分を計算し、JSON で返します。
+func GetDiff(spec1 *load.SpecInfo, spec2 *load.SpecInfo) ([]byte, error) {
+	changes, err := GetChanges(spec1, spec2, nil)
 	if err != nil {
 		return []byte{}, err
 	}
+	return json.Marshal(changes)
+}
+
+// GetChanges は base から revision への変更を計算して返します。
+// localizer が nil の場合は日本語のメッセージを使います。
+func GetChanges(base *load.SpecInfo, revision *load.SpecInfo, localizer *Localizer) (downloader.Diff, error) {
+	changes, _, err := compare(base, revision, localizer)
+	return changes, err
+}
+
+// CompareSpecs は base から revision への変更と構造的な差分を返します。
+// localizer が nil の場合は日本語のメッセージを使います。
+func CompareSpecs(base *load.SpecInfo, revision *load.SpecInfo, localizer *Localizer) (downloader.Diff, downloader.StructuralDiff, error) {
+	return compare(base, revision, localizer)
+}
+
+// CompareDocs は解析済みの2つのドキュメントについて、base から revision への変更と構造的な差分を返します。
+func CompareDocs(base *parser.APIDocument, revision *parser.APIDocument, localizer *Localizer) (downloader.Diff, downloader.StructuralDiff, error) {
+	baseInfo := specInfoFromDoc(base)
+	revisionInfo := specInfoFromDoc(revision)
+	return compare(&baseInfo, &revisionInfo, localizer)
+}
+
+// compare は base から revision への変更と構造的な差分を、oasdiff の差分を1度だけ計算して返します。
+func compare(base *load.SpecInfo, revision *load.SpecInfo, localizer *Localizer) (downloader.Diff, downloader.StructuralDiff, error) {
+	if localizer == nil {
+		localizer = defaultLocalizer()
+	}
+
+	diffConfig := diff.NewConfig()
 
-	return changelog, nil
+	diff, sourcesMap, err := diff.GetWithOperationsSourcesMap(diffConfig, base, revision)
+	if err != nil {
+		return nil, downloader.StructuralDiff{}, err
+	}
+	structural, err := structuralDiff(diff)
+	if err != nil {
+		return nil, downloader.StructuralDiff{}, err
+	}
+
+	checkConfig := checker.NewConfig(checker.GetAllChecks())
+
+	checks := checker.CheckBackwardCompatibilityUntilLevel(checkConfig, diff, sourcesMap, checker.INFO)
+	renames := detectRenames(diff)
+	annotateStructuralRenames(&structural, renames)
+
+	changes := make(downloader.Diff, 0, len(checks))
+	for _, change := range formatters.NewChanges(checks, localizer.Localize) {
+		changes = append(changes, downloader.Change{
+			Id:          change.Id,
+			Level:       int(change.Level),
+			Operation:   change.Operation,
+			OperationId: change.OperationId,
+			Path:        change.Path,
+			Section:     change.Section,
+			Text:        change.Text,
+			Comment:     change.Comment,
+			Source:      change.Source,
+			Attributes:  change.Attributes,
+		})
+	}
+	annotateRenames(changes, checks, renames, localizer)
+	return changes, structural, nil
 }
 
 var localizations = map[string]string{
@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"fmt"
+	localizations2 "github.com/oasdiff/oasdiff/checker/localizations"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// LocaleJa は同梱の日本語メッセージを使うロケールです。
+	LocaleJa = "ja"
+	// LocaleEn は oasdiff 組み込みの英語メッセージを使うロケールです。
+	LocaleEn = localizations2.LangEn
+
+	// fallbackLocale は翻訳が見つからない場合に使うロケールです。
+	fallbackLocale = LocaleEn
+)
+
+// Localizer は差分メッセージを指定したロケールに翻訳し、翻訳が見つからなかったキーを記録します。
+type Localizer struct {
+	locale   string
+	messages map[string]string
+
+	mu           sync.Mutex
+	untranslated map[string]struct{}
+}
+
+// NewLocalizer は locale のメッセージを使う Localizer を返します。
+// translationsFile が指定されている場合は、そのファイルの翻訳で組み込みの翻訳を上書き・追加します。
+// ファイルは ロケール → メッセージキー → 書式 の YAML (または JSON) です。
+func NewLocalizer(locale string, translationsFile string) (*Localizer, error) {
+	messages := make(map[string]string)
+	for k, v := range localizations2.New(fallbackLocale, fallbackLocale).Localizations {
+		messages[k] = v
+	}
+	for k, v := range renameLocalizations {
+		messages[k] = v
+	}
+	for k, v := range localizations {
+		messages[k] = v
+	}
+
+	if translationsFile != "" {
+		data, err := os.ReadFile(translationsFile)
+		if err != nil {
+			return nil, fmt.Errorf("翻訳ファイル '%s' の読み込みに失敗しました: %w", translationsFile, err)
+		}
+		var translations map[string]map[string]string
+		if err := yaml.Unmarshal(data, &translations); err != nil {
+			return nil, fmt.Errorf("翻訳ファイル '%s' の解析に失敗しました: %w", translationsFile, err)
+		}
+		for l, entries := range translations {
+			for key, pattern := range entries {
+				messages[messageKey(l, key)] = pattern
+			}
+		}
+	}
+
+	l := &Localizer{locale: locale, messages: messages, untranslated: make(map[string]struct{})}
+	if !l.supports(locale) {
+		return nil, fmt.Errorf("未対応のロケールです: %s (%s)", locale, strings.Join(l.Locales(), ", "))
+	}
+	return l, nil
+}
+
+// defaultLocalizer は Options に Localizer が指定されていない場合に使う日本語の Localizer です。
+var defaultLocalizer = sync.OnceValue(func() *Localizer {
+	l, err := NewLocalizer(LocaleJa, "")
+	if err != nil {
+		panic(err)
+	}
+	return l
+})
+
+// Locale は翻訳先のロケールを返します。
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// Locales は翻訳が1件以上あるロケールを返します。
+func (l *Localizer) Locales() []string {
+	seen := make(map[string]struct{})
+	for k := range l.messages {
+		if locale, _, ok := strings.Cut(k, ".messages."); ok {
+			seen[locale] = struct{}{}
+		}
+	}
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func (l *Localizer) supports(locale string) bool {
+	for _, s := range l.Locales() {
+		if s == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Localize は oasdiff のメッセージキーを翻訳して書式を適用します。
+// 指定したロケールに翻訳がなければ英語を使い、キーを未翻訳として記録します。
+func (l *Localizer) Localize(key string, args ...interface{}) string {
+	// コメントのない変更ではキーが空になる
+	if key == "" {
+		return ""
+	}
+	pattern, ok := l.messages[messageKey(l.locale, key)]
+	if !ok {
+		l.mu.Lock()
+		l.untranslated[key] = struct{}{}
+		l.mu.Unlock()
+
+		pattern, ok = l.messages[messageKey(fallbackLocale, key)]
+		if !ok {
+			return key
+		}
+	}
+	return fmt.Sprintf(pattern, args...)
+}
+
+// Untranslated はこれまでに翻訳が見つからなかったメッセージキーをソートして返します。
+func (l *Localizer) Untranslated() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := make([]string, 0, len(l.untranslated))
+	for k := range l.untranslated {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func messageKey(locale string, key string) string {
+	return locale + ".messages." + key
+}
@@ -0,0 +1,56 @@
+package diff
+
+import "runtime"
+
+// Options は差分計算の設定です。
+type Options struct {
+	// Strategy は差分を計算するバージョンの組み合わせ
+	Strategy Strategy
+	// Workers は同時に実行する差分計算の数。0以下なら CPU 数を使います。
+	Workers int
+	// Localizer は差分メッセージの翻訳に使います。nil なら日本語を使います。
+	Localizer *Localizer
+	// Rules は既知の変更の承認と重要度の上書きです。nil なら適用しません。
+	Rules *Rules
+	// Logf はバージョンの組ごとの進捗の出力先です。nil なら何も出力しません。
+	// 差分計算と同じく並列に呼ばれることがあります。
+	Logf func(format string, args ...interface{})
+}
+
+// workers は実際に使う並列数を返します。
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.NumCPU()
+}
+
+// localizer は実際に使う Localizer を返します。
+func (o Options) localizer() *Localizer {
+	if o.Localizer != nil {
+		return o.Localizer
+	}
+	return defaultLocalizer()
+}
+
+func (o Options) logf(format string, args ...interface{}) {
+	if o.Logf != nil {
+		o.Logf(format, args...)
+	}
+}
+
+// limiter は同時に実行できる処理の数を制限するセマフォです。
+type limiter chan struct{}
+
+// newLimiter は n 並列まで許可する limiter を返します。
+func newLimiter(n int) limiter {
+	return make(limiter, n)
+}
+
+func (l limiter) acquire() {
+	l <- struct{}{}
+}
+
+func (l limiter) release() {
+	<-l
+}
@@ -0,0 +1,326 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/oasdiff/checker"
+	"github.com/oasdiff/oasdiff/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// renameThreshold は名前の変更とみなす類似度の下限です。
+const renameThreshold = 0.6
+
+// 類似度のうち構造と説明の重み
+const (
+	structureWeight   = 0.7
+	descriptionWeight = 0.3
+)
+
+// renameLocalizations は名前の変更の注記のメッセージです。
+var renameLocalizations = map[string]string{
+	"en.messages.likely-renamed-from": "likely renamed from %s",
+	"en.messages.likely-renamed-to":   "likely renamed to %s",
+	"ja.messages.likely-renamed-from": "%s から名前が変更された可能性があります",
+	"ja.messages.likely-renamed-to":   "%s に名前が変更された可能性があります",
+}
+
+// renames は削除と追加の組から推定した名前の変更です。
+// オペレーションは "GET /pets" 形式のキー、スキーマはスキーマ名で対応付けます。
+type renames struct {
+	operationsTo   map[string]string // 変更前 → 変更後
+	operationsFrom map[string]string // 変更後 → 変更前
+	schemasTo      map[string]string
+	schemasFrom    map[string]string
+}
+
+// renameCandidate は削除または追加されたオペレーションかスキーマです。
+type renameCandidate struct {
+	key         string
+	method      string
+	operationID string
+	features    map[string]struct{}
+	description string
+}
+
+// detectRenames は削除されたオペレーション・スキーマと追加されたものを対応付け、名前の変更を推定します。
+// オペレーションは operationId が一致するものを優先し、残りは同じメソッドの中で構造と説明の類似度が高い組を選びます。
+func detectRenames(d *diff.Diff) renames {
+	r := renames{
+		operationsTo:   make(map[string]string),
+		operationsFrom: make(map[string]string),
+		schemasTo:      make(map[string]string),
+		schemasFrom:    make(map[string]string),
+	}
+	if d == nil {
+		return r
+	}
+
+	if d.PathsDiff != nil {
+		removed, added := changedOperations(d.PathsDiff)
+		matchCandidates(removed, added, r.operationsTo, r.operationsFrom)
+	}
+	if d.ComponentsDiff.SchemasDiff != nil {
+		schemas := d.ComponentsDiff.SchemasDiff
+		var removed, added []renameCandidate
+		for _, name := range schemas.Deleted {
+			removed = append(removed, schemaCandidate(name, schemas.Base[name]))
+		}
+		for _, name := range schemas.Added {
+			added = append(added, schemaCandidate(name, schemas.Revision[name]))
+		}
+		matchCandidates(removed, added, r.schemasTo, r.schemasFrom)
+	}
+	return r
+}
+
+// changedOperations は削除・追加されたパスのオペレーションと、既存のパスで削除・追加されたオペレーションを返します。
+func changedOperations(paths *diff.PathsDiff) (removed []renameCandidate, added []renameCandidate) {
+	if paths.Base != nil {
+		for _, path := range paths.Deleted {
+			removed = append(removed, pathCandidates(path, paths.Base.Value(path), nil)...)
+		}
+	}
+	if paths.Revision != nil {
+		for _, path := range paths.Added {
+			added = append(added, pathCandidates(path, paths.Revision.Value(path), nil)...)
+		}
+	}
+	for path, pathDiff := range paths.Modified {
+		if pathDiff.OperationsDiff == nil {
+			continue
+		}
+		removed = append(removed, pathCandidates(path, pathDiff.Base, pathDiff.OperationsDiff.Deleted)...)
+		added = append(added, pathCandidates(path, pathDiff.Revision, pathDiff.OperationsDiff.Added)...)
+	}
+	return removed, added
+}
+
+// pathCandidates はパスのオペレーションのうち methods に含まれるもの (nil なら全て) を返します。
+func pathCandidates(path string, pathItem *openapi3.PathItem, methods []string) []renameCandidate {
+	if pathItem == nil {
+		return nil
+	}
+	var candidates []renameCandidate
+	for method, op := range pathItem.Operations() {
+		if methods != nil && !slices.Contains(methods, method) {
+			continue
+		}
+		candidates = append(candidates, operationCandidate(method, path, op))
+	}
+	return candidates
+}
+
+func operationCandidate(method string, path string, op *openapi3.Operation) renameCandidate {
+	features := make(map[string]struct{})
+	for _, ref := range op.Parameters {
+		if ref != nil && ref.Value != nil {
+			features["parameter:"+ref.Value.In+":"+ref.Value.Name] = struct{}{}
+		}
+	}
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		for mimeType, mediaType := range op.RequestBody.Value.Content {
+			features["request:"+mimeType+":"+schemaFingerprint(mediaType.Schema)] = struct{}{}
+		}
+	}
+	if op.Responses != nil {
+		for code, ref := range op.Responses.Map() {
+			features["response:"+code] = struct{}{}
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			for mimeType, mediaType := range ref.Value.Content {
+				features["response:"+code+":"+mimeType+":"+schemaFingerprint(mediaType.Schema)] = struct{}{}
+			}
+		}
+	}
+	return renameCandidate{
+		key:         method + " " + path,
+		method:      method,
+		operationID: op.OperationID,
+		features:    features,
+		description: strings.TrimSpace(op.Summary + "\n" + op.Description),
+	}
+}
+
+func schemaCandidate(name string, ref *openapi3.SchemaRef) renameCandidate {
+	features := make(map[string]struct{})
+	description := ""
+	if ref != nil && ref.Value != nil {
+		schema := ref.Value
+		description = strings.TrimSpace(schema.Title + "\n" + schema.Description)
+		if schema.Type != nil {
+			for _, t := range *schema.Type {
+				features["type:"+t] = struct{}{}
+			}
+		}
+		for prop, propRef := range schema.Properties {
+			features["property:"+prop+":"+schemaFingerprint(propRef)] = struct{}{}
+		}
+		for _, required := range schema.Required {
+			features["required:"+required] = struct{}{}
+		}
+		for _, value := range schema.Enum {
+			features[fmt.Sprintf("enum:%v", value)] = struct{}{}
+		}
+	}
+	return renameCandidate{key: name, features: features, description: description}
+}
+
+// schemaFingerprint はスキーマを比べるための短い表現を返します。参照であれば参照先、それ以外は型とプロパティ名です。
+func schemaFingerprint(ref *openapi3.SchemaRef) string {
+	if ref == nil {
+		return ""
+	}
+	if ref.Ref != "" {
+		return ref.Ref
+	}
+	if ref.Value == nil {
+		return ""
+	}
+	var parts []string
+	if ref.Value.Type != nil {
+		parts = append(parts, ref.Value.Type.Slice()...)
+	}
+	props := make([]string, 0, len(ref.Value.Properties))
+	for prop := range ref.Value.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	return strings.Join(append(parts, props...), ",")
+}
+
+// matchCandidates は removed と added を1対1で対応付け、結果を to と from に記録します。
+func matchCandidates(removed []renameCandidate, added []renameCandidate, to map[string]string, from map[string]string) {
+	if len(removed) == 0 || len(added) == 0 {
+		return
+	}
+	// map の走査順によらず同じ結果になるよう並べておく
+	sort.Slice(removed, func(i, j int) bool { return removed[i].key < removed[j].key })
+	sort.Slice(added, func(i, j int) bool { return added[i].key < added[j].key })
+
+	for _, a := range removed {
+		if a.operationID == "" {
+			continue
+		}
+		for _, b := range added {
+			if b.operationID == a.operationID && from[b.key] == "" {
+				to[a.key] = b.key
+				from[b.key] = a.key
+				break
+			}
+		}
+	}
+
+	type pair struct {
+		removed, added string
+		score          float64
+	}
+	var pairs []pair
+	for _, a := range removed {
+		if _, ok := to[a.key]; ok {
+			continue
+		}
+		for _, b := range added {
+			if _, ok := from[b.key]; ok || a.method != b.method {
+				continue
+			}
+			if score := similarity(a, b); score >= renameThreshold {
+				pairs = append(pairs, pair{removed: a.key, added: b.key, score: score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].score > pairs[j].score })
+	for _, p := range pairs {
+		if _, ok := to[p.removed]; ok {
+			continue
+		}
+		if _, ok := from[p.added]; ok {
+			continue
+		}
+		to[p.removed] = p.added
+		from[p.added] = p.removed
+	}
+}
+
+// similarity は構造 (特徴の Jaccard 係数) と説明の一致から 0 〜 1 の類似度を返します。
+// 特徴が1つしかないものは偶然一致しやすいため、構造の類似度を 0 とします。
+func similarity(a renameCandidate, b renameCandidate) float64 {
+	structure := 0.0
+	if len(a.features) > 1 || len(b.features) > 1 {
+		common := 0
+		for feature := range a.features {
+			if _, ok := b.features[feature]; ok {
+				common++
+			}
+		}
+		structure = float64(common) / float64(len(a.features)+len(b.features)-common)
+	}
+	description := 0.0
+	if a.description != "" && a.description == b.description {
+		description = 1
+	}
+	return structureWeight*structure + descriptionWeight*description
+}
+
+// annotateRenames は名前の変更と推定した削除・追加の変更に、変更前後の名前と注記を追加します。
+// changes は checks を formatters.NewChanges で変換したもので、同じ順に並んでいる必要があります。
+func annotateRenames(changes downloader.Diff, checks checker.Changes, r renames, localizer *Localizer) {
+	for i := range changes {
+		change := &changes[i]
+		key := change.Operation + " " + change.Path
+		switch {
+		case change.Id == checker.EndpointAddedId:
+			change.RenamedFrom = r.operationsFrom[key]
+		case strings.HasPrefix(change.Id, "api-path-removed") || strings.HasPrefix(change.Id, "api-removed"):
+			change.RenamedTo = r.operationsTo[key]
+		case change.Id == checker.APISchemasRemovedId:
+			if args := checks[i].GetArgs(); len(args) > 0 {
+				change.RenamedTo = r.schemasTo[fmt.Sprint(args[0])]
+			}
+		}
+
+		var note string
+		switch {
+		case change.RenamedFrom != "":
+			note = localizer.Localize("likely-renamed-from", change.RenamedFrom)
+		case change.RenamedTo != "":
+			note = localizer.Localize("likely-renamed-to", change.RenamedTo)
+		default:
+			continue
+		}
+		if change.Comment != "" {
+			change.Comment += "\n"
+		}
+		change.Comment += note
+	}
+}
+
+// annotateStructuralRenames は構造的な差分の追加・削除されたオペレーションとスキーマに、変更前後の名前を記録します。
+func annotateStructuralRenames(d *downloader.StructuralDiff, r renames) {
+	for path, methods := range d.Operations {
+		for method, fields := range methods {
+			annotateFieldRenames(fields, method+" "+path, r.operationsFrom, r.operationsTo)
+		}
+	}
+	for name, fields := range d.Schemas {
+		annotateFieldRenames(fields, name, r.schemasFrom, r.schemasTo)
+	}
+}
+
+func annotateFieldRenames(fields []downloader.FieldChange, key string, from map[string]string, to map[string]string) {
+	for i := range fields {
+		if len(fields[i].Path) > 0 {
+			continue
+		}
+		switch fields[i].Kind {
+		case KindAdded:
+			fields[i].RenamedFrom = from[key]
+		case KindDeleted:
+			fields[i].RenamedTo = to[key]
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"maps"
+	"math"
+	"testing"
+)
+
+func features(names ...string) map[string]struct{} {
+	m := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		m[name] = struct{}{}
+	}
+	return m
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b renameCandidate
+		want float64
+	}{
+		{"構造と説明が一致", renameCandidate{features: features("x", "y"), description: "ペット"}, renameCandidate{features: features("x", "y"), description: "ペット"}, 1},
+		{"構造だけ一致", renameCandidate{features: features("x", "y")}, renameCandidate{features: features("x", "y")}, structureWeight},
+		{"構造の一部が一致", renameCandidate{features: features("x", "y")}, renameCandidate{features: features("y", "z")}, structureWeight / 3},
+		{"特徴が1つだけなら構造は数えない", renameCandidate{features: features("x"), description: "ペット"}, renameCandidate{features: features("x"), description: "ペット"}, descriptionWeight},
+		{"片方の特徴が複数なら構造を数える", renameCandidate{features: features("x")}, renameCandidate{features: features("x", "y")}, structureWeight / 2},
+		{"説明が異なる", renameCandidate{features: features("x", "y"), description: "a"}, renameCandidate{features: features("x", "y"), description: "b"}, structureWeight},
+		{"空の説明は一致とみなさない", renameCandidate{}, renameCandidate{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("similarity() = %v, want %v", got, tt.want)
+			}
+			if got := similarity(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("similarity() が対称ではありません: %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchCandidates(t *testing.T) {
+	pet := features("parameter:path:petId", "response:200", "response:404")
+	tests := []struct {
+		name    string
+		removed []renameCandidate
+		added   []renameCandidate
+		want    map[string]string
+	}{
+		{
+			"operationId が一致",
+			[]renameCandidate{{key: "GET /pets", method: "GET", operationID: "listPets", features: features("a", "b")}},
+			[]renameCandidate{{key: "GET /animals", method: "GET", operationID: "listPets", features: features("c", "d")}},
+			map[string]string{"GET /pets": "GET /animals"},
+		},
+		{
+			"構造が似ている",
+			[]renameCandidate{{key: "GET /pets/{petId}", method: "GET", features: pet}},
+			[]renameCandidate{{key: "GET /animals/{petId}", method: "GET", features: pet}},
+			map[string]string{"GET /pets/{petId}": "GET /animals/{petId}"},
+		},
+		{
+			"メソッドが異なる",
+			[]renameCandidate{{key: "GET /pets/{petId}", method: "GET", features: pet}},
+			[]renameCandidate{{key: "DELETE /animals/{petId}", method: "DELETE", features: pet}},
+			map[string]string{},
+		},
+		{
+			"類似度が閾値未満",
+			[]renameCandidate{{key: "GET /pets", method: "GET", features: features("a", "b", "c")}},
+			[]renameCandidate{{key: "GET /animals", method: "GET", features: features("a", "d", "e")}},
+			map[string]string{},
+		},
+		{
+			"類似度が高い組を優先して1対1で対応付ける",
+			[]renameCandidate{
+				{key: "GET /a", method: "GET", features: features("w", "x", "y", "z")},
+				{key: "GET /b", method: "GET", features: features("w", "x", "y", "z"), description: "ペット"},
+			},
+			[]renameCandidate{{key: "GET /c", method: "GET", features: features("w", "x", "y", "z"), description: "ペット"}},
+			map[string]string{"GET /b": "GET /c"},
+		},
+		{
+			"operationId の一致を類似度より優先",
+			[]renameCandidate{
+				{key: "GET /a", method: "GET", features: pet},
+				{key: "GET /b", method: "GET", operationID: "getPet", features: features("x", "y")},
+			},
+			[]renameCandidate{{key: "GET /c", method: "GET", operationID: "getPet", features: pet}},
+			map[string]string{"GET /b": "GET /c"},
+		},
+		{
+			"追加がない",
+			[]renameCandidate{{key: "Pet", features: features("a", "b")}},
+			nil,
+			map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := make(map[string]string)
+			from := make(map[string]string)
+			matchCandidates(tt.removed, tt.added, to, from)
+			if !maps.Equal(to, tt.want) {
+				t.Errorf("to = %v, want %v", to, tt.want)
+			}
+			for removed, added := range to {
+				if from[added] != removed {
+					t.Errorf("from[%q] = %q, want %q", added, from[added], removed)
+				}
+			}
+			if len(from) != len(to) {
+				t.Errorf("from = %v, to = %v の件数が一致しません", from, to)
+			}
+		})
+	}
+}
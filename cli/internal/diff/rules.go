@@ -0,0 +1,147 @@
+package diff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Rules は意図的に行った変更を承認する無視ルールと、チェックごとの重要度の上書きです。
+// 無視ルールに一致した変更は削除せず、承認済み (acknowledged) として記録します。
+//
+//	ignore:
+//	  - id: api-path-removed-without-deprecation
+//	    operation: GET
+//	    path: /pets/{petId}
+//	    versions: ">=2.0.0 <3.0.0"
+//	    reason: 2.0.0 で /animals に移行済み
+//	severity:
+//	  response-optional-property-added: WARN
+type Rules struct {
+	Ignore   []IgnoreRule      `yaml:"ignore"`
+	Severity map[string]string `yaml:"severity"`
+
+	levels map[string]int
+}
+
+// IgnoreRule は承認する変更の条件です。ID 以外の条件は省略すると全てに一致します。
+type IgnoreRule struct {
+	// ID はチェックの ID です。path.Match のパターンを使えます。
+	ID string `yaml:"id"`
+	// Operation は HTTP メソッドです。大文字小文字は区別しません。
+	Operation string `yaml:"operation,omitempty"`
+	// Path は API のパスです。path.Match のパターンを使えます。
+	Path string `yaml:"path,omitempty"`
+	// Versions は変更が入ったバージョン (比較する2つのうち新しい方) の範囲です。
+	// build、diff、check のいずれでも、ディレクトリ名ではなく仕様書の info.version と照合します。
+	Versions string `yaml:"versions,omitempty"`
+	// Reason は承認した理由です。
+	Reason string `yaml:"reason,omitempty"`
+
+	versions *semver.Range
+}
+
+// LoadRules はルールファイルを読み込んで検証します。
+func LoadRules(file string) (*Rules, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("ルールファイル '%s' の読み込みに失敗しました: %w", file, err)
+	}
+	rules := &Rules{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("ルールファイル '%s' の解析に失敗しました: %w", file, err)
+	}
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("ルールファイル '%s' が不正です: %w", file, err)
+	}
+	return rules, nil
+}
+
+// compile はルールを検証し、照合に使う値を準備します。
+func (r *Rules) compile() error {
+	var errs []error
+	for i := range r.Ignore {
+		rule := &r.Ignore[i]
+		if rule.ID == "" {
+			errs = append(errs, fmt.Errorf("ignore[%d].id: 指定してください", i))
+		} else if _, err := path.Match(rule.ID, ""); err != nil {
+			errs = append(errs, fmt.Errorf("ignore[%d].id: 不正なパターンです: %s", i, rule.ID))
+		}
+		if _, err := path.Match(rule.Path, ""); err != nil {
+			errs = append(errs, fmt.Errorf("ignore[%d].path: 不正なパターンです: %s", i, rule.Path))
+		}
+		if rule.Versions != "" {
+			versions, err := semver.ParseRange(rule.Versions)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("ignore[%d].versions: %w", i, err))
+			}
+			rule.versions = &versions
+		}
+	}
+
+	r.levels = make(map[string]int)
+	for id, name := range r.Severity {
+		level, err := downloader.ParseLevel(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("severity.%s: %w", id, err))
+		}
+		r.levels[id] = level
+	}
+	return errors.Join(errs...)
+}
+
+// Apply は base から revision への変更に重要度の上書きと無視ルールを適用した結果を返します。
+// base と revision には比較した仕様書の info.version を渡します。r が nil の場合は changes をそのまま返します。
+func (r *Rules) Apply(changes downloader.Diff, base string, revision string) downloader.Diff {
+	if r == nil {
+		return changes
+	}
+	introduced := revision
+	if semver.Compare(base, revision) > 0 {
+		introduced = base
+	}
+
+	result := make(downloader.Diff, len(changes))
+	for i, change := range changes {
+		if level, ok := r.levels[change.Id]; ok && level != change.Level {
+			change.OriginalLevel = change.Level
+			change.Level = level
+		}
+		for _, rule := range r.Ignore {
+			if rule.matches(change, introduced) {
+				change.Acknowledged = true
+				change.Reason = rule.Reason
+				break
+			}
+		}
+		result[i] = change
+	}
+	return result
+}
+
+func (rule IgnoreRule) matches(change downloader.Change, version string) bool {
+	if ok, _ := path.Match(rule.ID, change.Id); !ok {
+		return false
+	}
+	if rule.Operation != "" && !strings.EqualFold(rule.Operation, change.Operation) {
+		return false
+	}
+	if rule.Path != "" {
+		if ok, _ := path.Match(rule.Path, change.Path); !ok {
+			return false
+		}
+	}
+	if rule.versions != nil && !rule.versions.Contains(version) {
+		return false
+	}
+	return true
+}
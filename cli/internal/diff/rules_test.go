@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"testing"
+)
+
+func TestRulesApply(t *testing.T) {
+	rules := &Rules{
+		Ignore: []IgnoreRule{
+			{ID: "api-path-removed-*", Operation: "get", Path: "/pets/*", Versions: ">=2.0.0 <3.0.0", Reason: "移行済み"},
+			{ID: "request-parameter-removed"},
+		},
+		Severity: map[string]string{"response-optional-property-added": "WARN"},
+	}
+	if err := rules.compile(); err != nil {
+		t.Fatalf("compile() error = %v", err)
+	}
+
+	removed := downloader.Change{Id: "api-path-removed-without-deprecation", Level: downloader.LevelErr, Operation: "GET", Path: "/pets/{petId}"}
+	tests := []struct {
+		name          string
+		change        downloader.Change
+		base          string
+		revision      string
+		acknowledged  bool
+		reason        string
+		level         int
+		originalLevel int
+	}{
+		{"条件に全て一致", removed, "1.0.0", "2.1.0", true, "移行済み", downloader.LevelErr, 0},
+		{"新しい方のバージョンで照合する", removed, "2.1.0", "1.0.0", true, "移行済み", downloader.LevelErr, 0},
+		{"バージョンの範囲外", removed, "2.0.0", "3.0.0", false, "", downloader.LevelErr, 0},
+		{"メソッドが違う", downloader.Change{Id: removed.Id, Level: downloader.LevelErr, Operation: "POST", Path: "/pets/{petId}"}, "1.0.0", "2.0.0", false, "", downloader.LevelErr, 0},
+		{"パスが違う", downloader.Change{Id: removed.Id, Level: downloader.LevelErr, Operation: "GET", Path: "/owners/{id}"}, "1.0.0", "2.0.0", false, "", downloader.LevelErr, 0},
+		{"ID だけの条件", downloader.Change{Id: "request-parameter-removed", Level: downloader.LevelWarn}, "1.0.0", "9.0.0", true, "", downloader.LevelWarn, 0},
+		{"重要度の上書き", downloader.Change{Id: "response-optional-property-added", Level: downloader.LevelInfo}, "1.0.0", "1.1.0", false, "", downloader.LevelWarn, downloader.LevelInfo},
+		{"一致しない変更はそのまま", downloader.Change{Id: "api-tag-added", Level: downloader.LevelInfo}, "1.0.0", "1.1.0", false, "", downloader.LevelInfo, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules.Apply(downloader.Diff{tt.change}, tt.base, tt.revision)[0]
+			if got.Acknowledged != tt.acknowledged || got.Reason != tt.reason {
+				t.Errorf("Acknowledged, Reason = %v, %q, want %v, %q", got.Acknowledged, got.Reason, tt.acknowledged, tt.reason)
+			}
+			if got.Level != tt.level || got.OriginalLevel != tt.originalLevel {
+				t.Errorf("Level, OriginalLevel = %d, %d, want %d, %d", got.Level, got.OriginalLevel, tt.level, tt.originalLevel)
+			}
+		})
+	}
+}
+
+func TestRulesApplyNil(t *testing.T) {
+	var rules *Rules
+	changes := downloader.Diff{{Id: "api-path-removed-without-deprecation", Level: downloader.LevelErr}}
+	if got := rules.Apply(changes, "1.0.0", "2.0.0"); len(got) != 1 || got[0].Acknowledged {
+		t.Errorf("Apply() = %v, want 変更をそのまま返す", got)
+	}
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/oasdiff/oasdiff/load"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"net/url"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+)
+
+// gitPrefix は Git のリビジョン上のファイルを指す指定の接頭辞です (git:<ref>:<path>)。
+const gitPrefix = "git:"
+
+// LoadSpec は比較対象の仕様を読み込みます。source には次のいずれかを指定します。
+//   - 仕様ファイルのパス
+//   - 仕様ファイルを1つだけ含むディレクトリ
+//   - http(s) の URL
+//   - git:<ref>:<path> (カレントディレクトリを含むリポジトリのリビジョン ref 上のファイル)
+func LoadSpec(source string) (*load.SpecInfo, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	if strings.HasPrefix(source, gitPrefix) {
+		return loadGitSpec(loader, source)
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		stat, err := os.Stat(source)
+		if err != nil {
+			return nil, fmt.Errorf("仕様ファイル '%s' が見つかりません: %w", source, err)
+		}
+		if stat.IsDir() {
+			source, err = findSpecFile(source)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	info, err := load.NewSpecInfo(loader, load.NewSource(source))
+	if err != nil {
+		return nil, fmt.Errorf("仕様ファイル '%s' の読み込みに失敗しました: %w", source, err)
+	}
+	return info, nil
+}
+
+// SourceFile は LoadSpec に渡した指定が指すファイルの、リポジトリのルートからの相対パスを "/" 区切りで返します。
+// git:<ref>:<path> の場合は path をそのまま返します。URL や、リポジトリの外のファイルの場合は空文字列を返します。
+func SourceFile(source string) string {
+	if strings.HasPrefix(source, gitPrefix) {
+		if _, path, ok := strings.Cut(strings.TrimPrefix(source, gitPrefix), ":"); ok {
+			return path
+		}
+		return ""
+	}
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return ""
+	}
+
+	abs, err := filepath.Abs(source)
+	if err != nil {
+		return ""
+	}
+	repo, err := git.PlainOpenWithOptions(filepath.Dir(abs), &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return ""
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(worktree.Filesystem.Root(), abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// findSpecFile はディレクトリ直下の仕様ファイルを探します。
+func findSpecFile(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("ディレクトリ '%s' の読み込みに失敗しました: %w", dir, err)
+	}
+	var found []string
+	for _, entry := range entries {
+		if entry.IsDir() || !parser.IsSpecFile(entry.Name()) {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+			found = append(found, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(found) != 1 {
+		return "", fmt.Errorf("ディレクトリ '%s' には仕様ファイルがちょうど1つ必要です (%d個見つかりました)", dir, len(found))
+	}
+	return found[0], nil
+}
+
+// loadGitSpec は git:<ref>:<path> で指定されたファイルをリポジトリから読み込みます。
+// 相対パスの外部参照は作業ツリーではなく、同じリビジョンのファイルとして解決します。
+func loadGitSpec(loader *openapi3.Loader, source string) (*load.SpecInfo, error) {
+	ref, path, ok := strings.Cut(strings.TrimPrefix(source, gitPrefix), ":")
+	if !ok || ref == "" || path == "" {
+		return nil, fmt.Errorf("git の指定は git:<ref>:<path> の形式で指定してください: %s", source)
+	}
+
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, fmt.Errorf("Git リポジトリを開けませんでした: %w", err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("リビジョン '%s' の解決に失敗しました: %w", ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("コミット '%s' の取得に失敗しました: %w", ref, err)
+	}
+	file, err := commit.File(filepath.ToSlash(path))
+	if err != nil {
+		return nil, fmt.Errorf("リビジョン '%s' にファイル '%s' が見つかりません: %w", ref, path, err)
+	}
+	contents, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme != "" || location.Host != "" {
+			return openapi3.DefaultReadFromURI(loader, location)
+		}
+		name := pathpkg.Clean(filepath.ToSlash(location.Path))
+		if pathpkg.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("外部参照 '%s' はリポジトリの外を指しています", location.Path)
+		}
+		referenced, err := commit.File(name)
+		if err != nil {
+			return nil, fmt.Errorf("リビジョン '%s' に外部参照のファイル '%s' が見つかりません: %w", ref, name, err)
+		}
+		contents, err := referenced.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("ファイル '%s' の読み込みに失敗しました: %w", name, err)
+		}
+		return []byte(contents), nil
+	}
+
+	doc, err := loader.LoadFromDataWithPath([]byte(contents), &url.URL{Path: filepath.ToSlash(path)})
+	if err != nil {
+		return nil, fmt.Errorf("仕様ファイル '%s' の読み込みに失敗しました: %w", source, err)
+	}
+	info := &load.SpecInfo{Url: source, Spec: doc}
+	if doc.Info != nil {
+		info.Version = doc.Info.Version
+	}
+	return info, nil
+}
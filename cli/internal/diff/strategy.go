@@ -0,0 +1,112 @@
+package diff
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"sort"
+	"strings"
+)
+
+// Strategy は差分を計算するバージョンの組み合わせの選び方です。
+type Strategy string
+
+const (
+	// StrategyConsecutive は隣り合うバージョン同士だけを比較します。
+	StrategyConsecutive Strategy = "consecutive"
+	// StrategyLatest は各バージョンを最新バージョンと比較します。
+	StrategyLatest Strategy = "latest"
+	// StrategyPreviousMajor は各バージョンを1つ前のメジャーバージョンの最新版と比較します。
+	StrategyPreviousMajor Strategy = "previous-major"
+	// StrategyFull は全てのバージョンの組み合わせを比較します。
+	StrategyFull Strategy = "full"
+)
+
+// Strategies は選択できる全ての戦略です。
+var Strategies = []Strategy{StrategyConsecutive, StrategyLatest, StrategyPreviousMajor, StrategyFull}
+
+// ParseStrategy は文字列を Strategy に変換します。
+func ParseStrategy(s string) (Strategy, error) {
+	for _, strategy := range Strategies {
+		if string(strategy) == s {
+			return strategy, nil
+		}
+	}
+	names := make([]string, 0, len(Strategies))
+	for _, strategy := range Strategies {
+		names = append(names, string(strategy))
+	}
+	return "", fmt.Errorf("未対応の差分戦略です: %s (%s)", s, strings.Join(names, ", "))
+}
+
+// pair は差分を計算するバージョンの組です。base から revision への変更を計算します。
+type pair struct {
+	base     string
+	revision string
+}
+
+// pairs はバージョンの一覧から差分を計算する組を選びます。
+// 比較ページで左右を入れ替えられるよう、選んだ組は両方向とも計算します。
+func (s Strategy) pairs(versions []string) []pair {
+	sorted := append([]string{}, versions...)
+	semver.Sort(sorted)
+
+	seen := make(map[pair]bool)
+	var pairs []pair
+	add := func(a string, b string) {
+		if a == b {
+			return
+		}
+		for _, p := range []pair{{a, b}, {b, a}} {
+			if !seen[p] {
+				seen[p] = true
+				pairs = append(pairs, p)
+			}
+		}
+	}
+
+	switch s {
+	case StrategyFull:
+		for _, a := range sorted {
+			for _, b := range sorted {
+				add(a, b)
+			}
+		}
+	case StrategyLatest:
+		if len(sorted) > 0 {
+			latest := sorted[len(sorted)-1]
+			for _, v := range sorted {
+				add(v, latest)
+			}
+		}
+	case StrategyPreviousMajor:
+		// メジャーバージョンごとの最新版
+		latestOfMajor := make(map[int]string)
+		var majors []int
+		for _, v := range sorted {
+			parsed, ok := semver.Parse(v)
+			if !ok {
+				continue
+			}
+			if _, exists := latestOfMajor[parsed.Major]; !exists {
+				majors = append(majors, parsed.Major)
+			}
+			latestOfMajor[parsed.Major] = v
+		}
+		sort.Ints(majors)
+		for _, v := range sorted {
+			parsed, ok := semver.Parse(v)
+			if !ok {
+				continue
+			}
+			i := sort.SearchInts(majors, parsed.Major)
+			if i > 0 {
+				add(v, latestOfMajor[majors[i-1]])
+			}
+		}
+	default:
+		for i := 1; i < len(sorted); i++ {
+			add(sorted[i-1], sorted[i])
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,188 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/oasdiff/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"sort"
+)
+
+// 項目の変更の種類
+const (
+	KindAdded    = "added"
+	KindDeleted  = "deleted"
+	KindModified = "modified"
+)
+
+// structuralDiff は oasdiff の差分をオペレーションとスキーマごとの項目の変更の一覧に変換します。
+func structuralDiff(d *diff.Diff) (downloader.StructuralDiff, error) {
+	result := downloader.StructuralDiff{}
+	if d == nil {
+		return result, nil
+	}
+
+	if d.PathsDiff != nil {
+		addPathOperations(&result, d.PathsDiff.Added, d.PathsDiff.Revision, KindAdded)
+		addPathOperations(&result, d.PathsDiff.Deleted, d.PathsDiff.Base, KindDeleted)
+		for path, pathDiff := range d.PathsDiff.Modified {
+			operations := pathDiff.OperationsDiff
+			if operations == nil {
+				continue
+			}
+			methods := make(map[string][]downloader.FieldChange)
+			for _, method := range operations.Added {
+				methods[method] = []downloader.FieldChange{{Path: []string{}, Kind: KindAdded}}
+			}
+			for _, method := range operations.Deleted {
+				methods[method] = []downloader.FieldChange{{Path: []string{}, Kind: KindDeleted}}
+			}
+			for method, methodDiff := range operations.Modified {
+				changes, err := fieldChanges(methodDiff)
+				if err != nil {
+					return result, fmt.Errorf("%s %s の差分の変換に失敗しました: %w", method, path, err)
+				}
+				if len(changes) > 0 {
+					methods[method] = changes
+				}
+			}
+			if len(methods) == 0 {
+				continue
+			}
+			if result.Operations == nil {
+				result.Operations = make(map[string]map[string][]downloader.FieldChange)
+			}
+			result.Operations[path] = methods
+		}
+	}
+
+	if d.ComponentsDiff.SchemasDiff != nil {
+		schemas := d.ComponentsDiff.SchemasDiff
+		result.Schemas = make(map[string][]downloader.FieldChange)
+		for _, name := range schemas.Added {
+			result.Schemas[name] = []downloader.FieldChange{{Path: []string{}, Kind: KindAdded}}
+		}
+		for _, name := range schemas.Deleted {
+			result.Schemas[name] = []downloader.FieldChange{{Path: []string{}, Kind: KindDeleted}}
+		}
+		for name, schemaDiff := range schemas.Modified {
+			changes, err := fieldChanges(schemaDiff)
+			if err != nil {
+				return result, fmt.Errorf("スキーマ %s の差分の変換に失敗しました: %w", name, err)
+			}
+			if len(changes) > 0 {
+				result.Schemas[name] = changes
+			}
+		}
+		if len(result.Schemas) == 0 {
+			result.Schemas = nil
+		}
+	}
+	return result, nil
+}
+
+// addPathOperations は追加・削除されたパスの全てのオペレーションを kind の変更として追加します。
+func addPathOperations(result *downloader.StructuralDiff, paths []string, spec *openapi3.Paths, kind string) {
+	if spec == nil {
+		return
+	}
+	for _, path := range paths {
+		pathItem := spec.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		methods := make(map[string][]downloader.FieldChange)
+		for method := range pathItem.Operations() {
+			methods[method] = []downloader.FieldChange{{Path: []string{}, Kind: kind}}
+		}
+		if len(methods) == 0 {
+			continue
+		}
+		if result.Operations == nil {
+			result.Operations = make(map[string]map[string][]downloader.FieldChange)
+		}
+		result.Operations[path] = methods
+	}
+}
+
+// fieldChanges は oasdiff の差分を JSON と同じ形に変換し、変更された項目ごとに平坦化します。
+func fieldChanges(d interface{}) ([]downloader.FieldChange, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	var tree interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	var changes []downloader.FieldChange
+	flatten(tree, []string{}, KindModified, false, &changes)
+	return changes, nil
+}
+
+// flatten は差分の木をたどり、変更された項目を changes に追加します。
+// oasdiff の差分では added と deleted の下が追加・削除された項目の名前、
+// modified の下が変更された項目、from と to だけを持つ値が変更前後の値になっています。
+// inModified は node が modified の直下にあり、キーが項目の名前であることを表します。
+func flatten(node interface{}, path []string, kind string, inModified bool, changes *[]downloader.FieldChange) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if kind == KindModified && !inModified && isValueDiff(v) {
+			*changes = append(*changes, downloader.FieldChange{Path: path, Kind: KindModified, From: v["from"], To: v["to"]})
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch {
+			case inModified || kind != KindModified:
+				flatten(v[key], appendPath(path, key), kind, false, changes)
+			case key == KindAdded || key == KindDeleted:
+				flatten(v[key], path, key, false, changes)
+			case key == KindModified:
+				flatten(v[key], path, kind, true, changes)
+			default:
+				flatten(v[key], appendPath(path, key), kind, false, changes)
+			}
+		}
+	case []interface{}:
+		if kind == KindModified {
+			*changes = append(*changes, downloader.FieldChange{Path: path, Kind: kind, To: v})
+			return
+		}
+		for _, item := range v {
+			if _, ok := item.(map[string]interface{}); ok {
+				flatten(item, path, kind, false, changes)
+				continue
+			}
+			*changes = append(*changes, downloader.FieldChange{Path: appendPath(path, fmt.Sprint(item)), Kind: kind})
+		}
+	default:
+		if kind == KindModified {
+			*changes = append(*changes, downloader.FieldChange{Path: path, Kind: kind, To: v})
+			return
+		}
+		*changes = append(*changes, downloader.FieldChange{Path: path, Kind: kind})
+	}
+}
+
+// isValueDiff は m が oasdiff の ValueDiff (from と to だけを持つ値) かを返します。
+func isValueDiff(m map[string]interface{}) bool {
+	if len(m) != 2 {
+		return false
+	}
+	_, hasFrom := m["from"]
+	_, hasTo := m["to"]
+	return hasFrom && hasTo
+}
+
+// appendPath は path を変更せずに末尾に segment を加えた経路を返します。
+func appendPath(path []string, segment string) []string {
+	result := make([]string, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, segment)
+}
@@ -122,11 +122,12 @@ func sanitizeStringForPath(s string) string {
 	return r.Replace(sanitized)
 }
 
-func Download(repoURL string, outputDirDownload string, maxVersions int) {
+// Download はGitリポジトリをクローンし、コミット履歴からOpenAPIファイルを収集して outputDirDownload に保存します。
+func Download(repoURL string, outputDirDownload string, maxVersions int) error {
 	// 一時ディレクトリを作成
 	tempDir, err := os.MkdirTemp("", "openapi-git-clone-")
 	if err != nil {
-		log.Fatalf("エラー: 一時ディレクトリの作成に失敗しました: %v", err)
+		return fmt.Errorf("一時ディレクトリの作成に失敗しました: %w", err)
 	}
 	defer os.RemoveAll(tempDir) // 処理の最後に一時ディレクトリをクリーンアップ
 
@@ -136,7 +137,7 @@ func Download(repoURL string, outputDirDownload string, maxVersions int) {
 		Progress: os.Stdout,
 	})
 	if err != nil {
-		log.Fatalf("エラー: リポジトリのクローンに失敗しました: %v", err)
+		return fmt.Errorf("リポジトリのクローンに失敗しました: %w", err)
 	}
 
 	fmt.Println("\nリポジトリのクローンが完了しました。")
@@ -144,14 +145,15 @@ func Download(repoURL string, outputDirDownload string, maxVersions int) {
 
 	// 出力ディレクトリを作成
 	if err := os.MkdirAll(outputDirDownload, 0755); err != nil {
-		log.Fatalf("エラー: 出力ディレクトリ '%s' の作成に失敗しました: %v", outputDirDownload, err)
+		return fmt.Errorf("出力ディレクトリ '%s' の作成に失敗しました: %w", outputDirDownload, err)
 	}
 
 	// コミット履歴を処理
 	err = processCommitHistory(repo, outputDirDownload, maxVersions)
 	if err != nil {
-		log.Fatalf("エラー: コミット履歴の処理中にエラーが発生しました: %v", err)
+		return fmt.Errorf("コミット履歴の処理中にエラーが発生しました: %w", err)
 	}
+	return nil
 }
 
 type Info struct {
@@ -170,4 +172,87 @@ type Change struct {
 	Path        string `json:"path"`
 	Section     string `json:"section"`
 	Text        string `json:"text"`
+	Comment     string `json:"comment,omitempty"`
+	// Source は変更を検出した仕様ファイルです。ビルドした環境のパスを含むため diff.json などには出力せず、
+	// check コマンドの注釈にだけ使います。
+	Source string `json:"-"`
+	// Attributes はチェックごとの追加情報です (例: 廃止日)
+	Attributes map[string]any `json:"attributes,omitempty"`
+	// Acknowledged は無視ルールで既知の変更として承認されたかを表します
+	Acknowledged bool   `json:"acknowledged,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+	// OriginalLevel は重要度の上書き前のレベルです。上書きされていなければ0です。
+	OriginalLevel int `json:"originalLevel,omitempty"`
+	// RenamedFrom と RenamedTo は削除と追加の組から名前の変更と推定した場合の変更前・変更後の名前です
+	// (オペレーションは "GET /pets" 形式、スキーマはスキーマ名)
+	RenamedFrom string `json:"renamedFrom,omitempty"`
+	RenamedTo   string `json:"renamedTo,omitempty"`
+}
+
+// StructuralDiffs は比較先のバージョンごとの構造的な差分です。
+type StructuralDiffs = map[string]StructuralDiff
+
+// StructuralDiff は互換性の判定に関係しない変更も含む、仕様の構造的な差分です。
+type StructuralDiff struct {
+	// Operations はパス、大文字の HTTP メソッドごとの項目の変更
+	Operations map[string]map[string][]FieldChange `json:"operations,omitempty"`
+	// Schemas はスキーマ名ごとの項目の変更
+	Schemas map[string][]FieldChange `json:"schemas,omitempty"`
+}
+
+// FieldChange は仕様の1つの項目の変更です。
+type FieldChange struct {
+	// Path はオペレーションやスキーマから変更された項目までの経路 (例: ["parameters", "query", "limit", "description"])
+	Path []string `json:"path"`
+	// Kind は "added"、"deleted"、"modified" のいずれか
+	Kind string      `json:"kind"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+	// RenamedFrom と RenamedTo は追加・削除されたオペレーションやスキーマ全体が名前の変更と推定された場合の名前
+	RenamedFrom string `json:"renamedFrom,omitempty"`
+	RenamedTo   string `json:"renamedTo,omitempty"`
+}
+
+// 変更と lint の問題のレベル。oasdiff の checker.Level と同じ値です。
+const (
+	LevelInfo = 1
+	LevelWarn = 2
+	LevelErr  = 3
+)
+
+// BreakingLevel は互換性を損なう変更とみなすレベルです。oasdiff の breaking と同じく WARN 以上を数えます。
+// バージョンの上げ方の検証、リリースノート、フィードはこの定義を共有します。
+const BreakingLevel = LevelWarn
+
+// Levels は --fail-on などに指定できるレベル名です。
+var Levels = []string{"ERR", "WARN", "INFO"}
+
+// ParseLevel はレベル名 (ERR, WARN, INFO) を数値に変換します。大文字小文字は区別しません。
+func ParseLevel(s string) (int, error) {
+	switch strings.ToUpper(s) {
+	case "ERR", "ERROR":
+		return LevelErr, nil
+	case "WARN", "WARNING":
+		return LevelWarn, nil
+	case "INFO":
+		return LevelInfo, nil
+	}
+	return 0, fmt.Errorf("未対応のレベルです: %s (ERR, WARN, INFO)", s)
+}
+
+// LevelName は変更のレベルを "ERR"、"WARN"、"INFO" のいずれかで返します。
+func (c Change) LevelName() string {
+	switch c.Level {
+	case LevelErr:
+		return "ERR"
+	case LevelWarn:
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
+
+// IsBreaking は変更が互換性を損なう変更かを返します。
+func (c Change) IsBreaking() bool {
+	return c.Level >= BreakingLevel
 }
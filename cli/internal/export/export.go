@@ -0,0 +1,160 @@
+package export
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/snippet"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// untaggedFolder はタグが付いていないオペレーションをまとめるフォルダ名です。
+const untaggedFolder = "タグなし"
+
+// pathParamPattern はパス中の {param} を表します。
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+// folder はタグごとにまとめたリクエストの集まりです。
+type folder struct {
+	Name     string
+	Requests []request
+}
+
+// request はエクスポート用に組み立てた1オペレーション分のリクエストです。
+// URL とヘッダーの値に含まれる {param} は、書き出す形式の変数の構文に expand で置き換えます。
+type request struct {
+	Name        string
+	OperationID string
+	Description string
+	Method      string
+	Server      string // ベースURLの変数名
+	URL         string // ベースURLを除いたパスとクエリ
+	Headers     []snippet.Header
+	MimeType    string
+	Body        string
+}
+
+// server は変数として宣言するベースURLです。
+type server struct {
+	Variable string
+	URL      string
+}
+
+// collection はエクスポート対象となる1つのAPIバージョンです。
+type collection struct {
+	Name        string
+	Description string
+	// Servers はオペレーションが使うサーバーの一覧です。最初のサーバーの変数名は baseUrl です。
+	Servers []server
+	// Variables は例がなく {param} のまま残したパラメータの名前です。
+	Variables []string
+	Folders   []folder
+}
+
+// url は変数の構文を返す variable を使って、ベースURLを含むリクエストの URL を組み立てます。
+func (r request) url(variable func(string) string) string {
+	return variable(r.Server) + expand(r.URL, variable)
+}
+
+// expand は s の中の {param} を variable が返す変数の構文に置き換えます。
+func expand(s string, variable func(string) string) string {
+	return pathParamPattern.ReplaceAllStringFunc(s, func(match string) string {
+		return variable(match[1 : len(match)-1])
+	})
+}
+
+// mustacheVariable は .http と Postman の {{name}} 形式の変数を返します。
+func mustacheVariable(name string) string {
+	return "{{" + name + "}}"
+}
+
+// buildCollection は APIDocument をタグごとのフォルダに分けたリクエストの集まりに変換します。
+func buildCollection(doc *parser.APIDocument) collection {
+	c := collection{Name: fmt.Sprintf("%s %s", doc.APIName, doc.Version)}
+	if doc.Doc.Info != nil {
+		c.Description = doc.Doc.Info.Description
+		if doc.Doc.Info.Title != "" {
+			c.Name = fmt.Sprintf("%s %s", doc.Doc.Info.Title, doc.Doc.Info.Version)
+		}
+	}
+
+	folderIndex := make(map[string]int)
+	serverIndex := make(map[string]int)
+	declared := make(map[string]bool)
+	declare := func(s string) {
+		for _, match := range pathParamPattern.FindAllStringSubmatch(s, -1) {
+			if !declared[match[1]] {
+				declared[match[1]] = true
+				c.Variables = append(c.Variables, match[1])
+			}
+		}
+	}
+	for _, op := range specutil.Operations(doc.Doc) {
+		req := snippet.BuildRequest(doc.Doc, op)
+		serverURL := specutil.ServerURL(doc.Doc, op)
+		if _, ok := serverIndex[serverURL]; !ok {
+			serverIndex[serverURL] = len(c.Servers)
+			c.Servers = append(c.Servers, server{Variable: serverVariable(len(c.Servers)), URL: serverURL})
+		}
+
+		tag := untaggedFolder
+		if len(op.Operation.Tags) > 0 {
+			tag = op.Operation.Tags[0]
+		}
+		if _, ok := folderIndex[tag]; !ok {
+			folderIndex[tag] = len(c.Folders)
+			c.Folders = append(c.Folders, folder{Name: tag})
+		}
+
+		url := strings.TrimPrefix(req.URL, serverURL)
+		declare(url)
+		for _, h := range req.Headers {
+			declare(h.Value)
+		}
+
+		i := folderIndex[tag]
+		c.Folders[i].Requests = append(c.Folders[i].Requests, request{
+			Name:        operationName(op),
+			OperationID: op.Operation.OperationID,
+			Description: op.Operation.Description,
+			Method:      req.Method,
+			Server:      c.Servers[serverIndex[serverURL]].Variable,
+			URL:         url,
+			Headers:     req.Headers,
+			MimeType:    req.MimeType,
+			Body:        req.Body,
+		})
+	}
+	return c
+}
+
+// serverVariable は i 番目のサーバーのベースURLの変数名 (baseUrl, baseUrl2, ...) を返します。
+func serverVariable(i int) string {
+	if i == 0 {
+		return "baseUrl"
+	}
+	return fmt.Sprintf("baseUrl%d", i+1)
+}
+
+// operationName はsummary、operationId、メソッドとパスの順にオペレーションの表示名を決めます。
+func operationName(op specutil.Operation) string {
+	if op.Operation.Summary != "" {
+		return op.Operation.Summary
+	}
+	if op.Operation.OperationID != "" {
+		return op.Operation.OperationID
+	}
+	return op.Key()
+}
+
+// versionDir は outputDir/export/apiName/version のディレクトリを作成して返します。
+func versionDir(doc *parser.APIDocument, outputDir string) (string, error) {
+	dir := filepath.Join(outputDir, "export", doc.APIName, doc.Version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("ディレクトリ '%s' の作成に失敗しました: %w", dir, err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteHTTPFile はAPIバージョンをJetBrains/VS Code (REST Client) の .http 形式で書き出します。
+func WriteHTTPFile(doc *parser.APIDocument, outputDir string) (string, error) {
+	c := buildCollection(doc)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", c.Name)
+	for _, server := range c.Servers {
+		fmt.Fprintf(&b, "@%s = %s\n", server.Variable, server.URL)
+	}
+	for _, name := range c.Variables {
+		fmt.Fprintf(&b, "@%s =\n", name)
+	}
+	for _, f := range c.Folders {
+		fmt.Fprintf(&b, "\n# ===== %s =====\n", f.Name)
+		for _, r := range f.Requests {
+			fmt.Fprintf(&b, "\n### %s\n", r.Name)
+			if r.OperationID != "" {
+				fmt.Fprintf(&b, "# @name %s\n", r.OperationID)
+			}
+			fmt.Fprintf(&b, "%s %s\n", r.Method, r.url(mustacheVariable))
+			for _, h := range r.Headers {
+				fmt.Fprintf(&b, "%s: %s\n", h.Name, expand(h.Value, mustacheVariable))
+			}
+			if r.Body != "" {
+				fmt.Fprintf(&b, "\n%s\n", r.Body)
+			}
+		}
+	}
+
+	dir, err := versionDir(doc, outputDir)
+	if err != nil {
+		return "", err
+	}
+	outputPath := filepath.Join(dir, "requests.http")
+	return outputPath, os.WriteFile(outputPath, []byte(b.String()), 0644)
+}
@@ -0,0 +1,116 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportDate   string             `json:"__export_date"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+// insomniaResource はワークスペース、環境、フォルダ、リクエストのいずれかを表します。
+type insomniaResource struct {
+	ID          string            `json:"_id"`
+	Type        string            `json:"_type"`
+	ParentID    *string           `json:"parentId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Data        map[string]string `json:"data,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Body        *insomniaBody     `json:"body,omitempty"`
+	Headers     []insomniaHeader  `json:"headers,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type insomniaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// identifierPattern は Insomnia のテンプレートで _.name の形で参照できる変数名です。
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// insomniaVariable は環境の変数を参照する Insomnia のテンプレート ({{ _.name }}) を返します。
+// 識別子として使えない名前は {{ _['name'] }} の形で参照します。
+func insomniaVariable(name string) string {
+	if identifierPattern.MatchString(name) {
+		return "{{ _." + name + " }}"
+	}
+	return "{{ _['" + strings.ReplaceAll(name, "'", "\\'") + "'] }}"
+}
+
+// WriteInsomnia はAPIバージョンをInsomniaのエクスポート形式 (v4) で書き出します。
+func WriteInsomnia(doc *parser.APIDocument, outputDir string) (string, error) {
+	c := buildCollection(doc)
+
+	workspaceID := "wrk_" + doc.APIName + "_" + doc.Version
+	environment := make(map[string]string)
+	for _, name := range c.Variables {
+		environment[name] = ""
+	}
+	for _, server := range c.Servers {
+		environment[server.Variable] = server.URL
+	}
+	export := insomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportDate:   doc.Info.Date.UTC().Format(time.RFC3339),
+		ExportSource: "openapi-static-document-generator",
+		Resources: []insomniaResource{
+			{ID: workspaceID, Type: "workspace", Name: c.Name, Description: c.Description},
+			{ID: "env_" + doc.APIName + "_" + doc.Version, Type: "environment", ParentID: &workspaceID, Name: "Base Environment", Data: environment},
+		},
+	}
+	for i, f := range c.Folders {
+		folderID := fmt.Sprintf("fld_%d", i)
+		export.Resources = append(export.Resources, insomniaResource{
+			ID: folderID, Type: "request_group", ParentID: &workspaceID, Name: f.Name,
+		})
+		for j, r := range f.Requests {
+			parentID := folderID
+			resource := insomniaResource{
+				ID:          fmt.Sprintf("req_%d_%d", i, j),
+				Type:        "request",
+				ParentID:    &parentID,
+				Name:        r.Name,
+				Description: r.Description,
+				Method:      r.Method,
+				URL:         r.url(insomniaVariable),
+			}
+			for _, h := range r.Headers {
+				resource.Headers = append(resource.Headers, insomniaHeader{Name: h.Name, Value: expand(h.Value, insomniaVariable)})
+			}
+			if r.Body != "" {
+				resource.Body = &insomniaBody{MimeType: r.MimeType, Text: r.Body}
+			}
+			export.Resources = append(export.Resources, resource)
+		}
+	}
+
+	dir, err := versionDir(doc, outputDir)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Insomniaエクスポートのマーシャリングに失敗しました: %w", err)
+	}
+	outputPath := filepath.Join(dir, "insomnia.json")
+	return outputPath, os.WriteFile(outputPath, data, 0644)
+}
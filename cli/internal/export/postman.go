@@ -0,0 +1,110 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"os"
+	"path/filepath"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name    string         `json:"name"`
+	Request postmanRequest `json:"request"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []postmanHeader `json:"header"`
+	URL         string          `json:"url"`
+	Body        *postmanBody    `json:"body,omitempty"`
+	Description string          `json:"description,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string                 `json:"mode"`
+	Raw     string                 `json:"raw"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// WritePostman はAPIバージョンをPostman Collection v2.1形式で書き出します。
+func WritePostman(doc *parser.APIDocument, outputDir string) (string, error) {
+	c := buildCollection(doc)
+
+	pc := postmanCollection{
+		Info: postmanInfo{
+			Name:        c.Name,
+			Description: c.Description,
+			Schema:      postmanSchema,
+		},
+	}
+	for _, server := range c.Servers {
+		pc.Variable = append(pc.Variable, postmanVariable{Key: server.Variable, Value: server.URL})
+	}
+	for _, name := range c.Variables {
+		pc.Variable = append(pc.Variable, postmanVariable{Key: name})
+	}
+	for _, f := range c.Folders {
+		pf := postmanFolder{Name: f.Name}
+		for _, r := range f.Requests {
+			pr := postmanRequest{
+				Method:      r.Method,
+				Header:      []postmanHeader{},
+				URL:         r.url(mustacheVariable),
+				Description: r.Description,
+			}
+			for _, h := range r.Headers {
+				pr.Header = append(pr.Header, postmanHeader{Key: h.Name, Value: expand(h.Value, mustacheVariable)})
+			}
+			if r.Body != "" {
+				pr.Body = &postmanBody{Mode: "raw", Raw: r.Body}
+				if specutil.IsJSON(r.MimeType) {
+					pr.Body.Options = map[string]interface{}{"raw": map[string]string{"language": "json"}}
+				}
+			}
+			pf.Item = append(pf.Item, postmanItem{Name: r.Name, Request: pr})
+		}
+		pc.Item = append(pc.Item, pf)
+	}
+
+	dir, err := versionDir(doc, outputDir)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.MarshalIndent(pc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Postmanコレクションのマーシャリングに失敗しました: %w", err)
+	}
+	outputPath := filepath.Join(dir, "postman_collection.json")
+	return outputPath, os.WriteFile(outputPath, data, 0644)
+}
@@ -0,0 +1,310 @@
+package feed
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"html"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Dir は出力先ディレクトリの中でフィードを書き出すディレクトリです。
+const Dir = "feeds"
+
+// aggregateName は全 API をまとめたフィードのファイル名 (拡張子を除く) です。
+const aggregateName = "all"
+
+// apiPrefix は API ごとのフィードのファイル名の接頭辞です。API 名がまとめたフィードと衝突しないようにします。
+const apiPrefix = "api-"
+
+// defaultAuthor は連絡先が記載されていない場合のフィードの作成者です。
+const defaultAuthor = "oasdoc"
+
+// entry は1つの API バージョンのフィードの項目です。
+type entry struct {
+	apiName  string
+	version  string
+	date     time.Time
+	link     string
+	breaking int
+	other    int
+	changes  downloader.Diff
+	// compared は1つ前のバージョンとの差分が計算されているかを表します
+	compared bool
+	previous string
+}
+
+// Write は API ごとと全 API をまとめた Atom フィードと JSON Feed を outputDir/feeds に書き出し、ディレクトリのパスを返します。
+// 項目のリンクは siteURL を基準にした Next.js サイトの比較ページです。siteURL が空の場合はサイトルートからの相対パスになります。
+func Write(siteData *generator.SiteData, outputDir string, siteURL string) (string, error) {
+	dir := filepath.Join(outputDir, Dir)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("フィードの出力先ディレクトリの作成に失敗しました: %w", err)
+	}
+	siteURL = strings.TrimSuffix(siteURL, "/")
+
+	var all []entry
+	for i := range siteData.APIs {
+		api := &siteData.APIs[i]
+		entries := apiEntries(api, siteURL)
+		all = append(all, entries...)
+		title := fmt.Sprintf("%s の変更履歴", api.Name)
+		if err := writeFeeds(dir, apiFileName(api.Name), title, apiAuthor(api), siteURL+"/docs/"+url.PathEscape(api.Name), siteURL, entries); err != nil {
+			return "", err
+		}
+	}
+	if err := writeFeeds(dir, aggregateName, "API の変更履歴", defaultAuthor, siteURL+"/", siteURL, all); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// apiFileName は API ごとのフィードのファイル名 (拡張子を除く) を返します。
+// "/" などを含む API 名でも feeds の外に書き出さないよう、名前はパスの要素としてエスケープします。
+func apiFileName(apiName string) string {
+	return apiPrefix + url.PathEscape(apiName)
+}
+
+// apiAuthor は最新のバージョンの info.contact.name を返します。記載がなければ defaultAuthor を返します。
+func apiAuthor(api *generator.API) string {
+	for i := len(api.Versions) - 1; i >= 0; i-- {
+		doc := api.Versions[i].Doc
+		if doc != nil && doc.Info != nil && doc.Info.Contact != nil && doc.Info.Contact.Name != "" {
+			return doc.Info.Contact.Name
+		}
+	}
+	return defaultAuthor
+}
+
+// apiEntries は API のバージョンごとに、1つ前のバージョンからの変更をまとめた項目を返します。
+func apiEntries(api *generator.API, siteURL string) []entry {
+	var entries []entry
+	for i, version := range api.Versions {
+		e := entry{
+			apiName: api.Name,
+			version: version.Version,
+			date:    version.Info.Date,
+			link:    siteURL + "/docs/" + url.PathEscape(api.Name) + "/" + url.PathEscape(version.Version),
+		}
+		if i > 0 {
+			prev := api.Versions[i-1]
+			e.previous = prev.Version
+			if changes, ok := prev.Diffs[version.Version]; ok {
+				e.compared = true
+				e.changes = changes
+				// 比較ページは /compare/<API>/<比較元>/<比較先> で base から revision への変更を表示する
+				e.link = siteURL + "/compare/" + url.PathEscape(api.Name) + "/" + url.PathEscape(prev.Version) + "/" + url.PathEscape(version.Version)
+				for _, change := range changes {
+					if change.IsBreaking() {
+						e.breaking++
+					} else {
+						e.other++
+					}
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func (e entry) id() string {
+	return "urn:oasdoc:" + url.PathEscape(e.apiName) + ":" + url.PathEscape(e.version)
+}
+
+func (e entry) title() string {
+	return fmt.Sprintf("%s %s", e.apiName, e.version)
+}
+
+// summary は変更の件数をまとめた1行の説明を返します。
+func (e entry) summary() string {
+	switch {
+	case e.previous == "":
+		return "最初のバージョンです。"
+	case !e.compared:
+		return fmt.Sprintf("%s との差分は計算されていません。", e.previous)
+	case e.breaking+e.other == 0:
+		return fmt.Sprintf("%s からの変更はありません。", e.previous)
+	default:
+		return fmt.Sprintf("%s からの変更: 互換性を損なう変更 %d件、その他の変更 %d件", e.previous, e.breaking, e.other)
+	}
+}
+
+// contentHTML は変更の一覧を HTML で返します。
+func (e entry) contentHTML() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(e.summary()))
+	if len(e.changes) == 0 {
+		return b.String()
+	}
+	b.WriteString("<ul>")
+	for _, change := range e.changes {
+		fmt.Fprintf(&b, "<li>[%s] %s", change.LevelName(), html.EscapeString(change.Text))
+		if change.Operation != "" {
+			fmt.Fprintf(&b, " (<code>%s %s</code>)", html.EscapeString(change.Operation), html.EscapeString(change.Path))
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
+
+// writeFeeds は entries を新しい順に並べ、name.atom.xml と name.json に書き出します。name はエスケープ済みのファイル名です。
+func writeFeeds(dir string, name string, title string, author string, homePage string, siteURL string, entries []entry) error {
+	sorted := make([]entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].date.After(sorted[j].date) })
+
+	atomFile := name + ".atom.xml"
+	jsonFile := name + ".json"
+	feedURL := func(file string) string {
+		if siteURL == "" {
+			return ""
+		}
+		return siteURL + "/" + Dir + "/" + url.PathEscape(file)
+	}
+
+	if err := writeAtom(filepath.Join(dir, atomFile), name, title, author, homePage, feedURL(atomFile), sorted); err != nil {
+		return err
+	}
+	return writeJSONFeed(filepath.Join(dir, jsonFile), title, homePage, feedURL(jsonFile), sorted)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomAuthor はフィードの作成者です。Atom では項目に作成者がない場合、フィードに必須です。
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID       string       `xml:"id"`
+	Title    string       `xml:"title"`
+	Updated  string       `xml:"updated"`
+	Link     atomLink     `xml:"link"`
+	Summary  string       `xml:"summary"`
+	Content  atomContent  `xml:"content"`
+	Category atomCategory `xml:"category"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func writeAtom(path string, name string, title string, author string, homePage string, self string, entries []entry) error {
+	feed := atomFeed{
+		ID:     "urn:oasdoc:feed:" + name,
+		Title:  title,
+		Author: atomAuthor{Name: author},
+		Links:  []atomLink{{Href: homePage}},
+	}
+	if self != "" {
+		feed.Links = append(feed.Links, atomLink{Href: self, Rel: "self"})
+	}
+	var updated time.Time
+	for _, e := range entries {
+		if e.date.After(updated) {
+			updated = e.date
+		}
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:       e.id(),
+			Title:    e.title(),
+			Updated:  e.date.UTC().Format(time.RFC3339),
+			Link:     atomLink{Href: e.link},
+			Summary:  e.summary(),
+			Content:  atomContent{Type: "html", Body: e.contentHTML()},
+			Category: atomCategory{Term: e.apiName},
+		})
+	}
+	feed.Updated = updated.UTC().Format(time.RFC3339)
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Atom フィードのエンコードに失敗しました: %w", err)
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if err := os.WriteFile(path, data, os.ModePerm); err != nil {
+		return fmt.Errorf("Atom フィードの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// jsonFeed は JSON Feed 1.1 (https://jsonfeed.org/version/1.1) の形式です。
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url,omitempty"`
+	Title         string   `json:"title"`
+	Summary       string   `json:"summary"`
+	ContentHTML   string   `json:"content_html"`
+	DatePublished string   `json:"date_published,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+func writeJSONFeed(path string, title string, homePage string, self string, entries []entry) error {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		HomePageURL: homePage,
+		FeedURL:     self,
+		Items:       []jsonFeedItem{},
+	}
+	for _, e := range entries {
+		item := jsonFeedItem{
+			ID:          e.id(),
+			URL:         e.link,
+			Title:       e.title(),
+			Summary:     e.summary(),
+			ContentHTML: e.contentHTML(),
+			Tags:        []string{e.apiName},
+		}
+		if !e.date.IsZero() {
+			item.DatePublished = e.date.UTC().Format(time.RFC3339)
+		}
+		if e.breaking > 0 {
+			item.Tags = append(item.Tags, "breaking")
+		}
+		feed.Items = append(feed.Items, item)
+	}
+
+	data, err := json.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON Feed のエンコードに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, data, os.ModePerm); err != nil {
+		return fmt.Errorf("JSON Feed の書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package generator
+
+import (
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/coverage"
+	"slices"
+)
+
+// CoverageReports は apiNames の API (空なら全て) のバージョンごとのドキュメントの充実度を古いバージョンから順に返します。
+func CoverageReports(siteData *SiteData, apiNames []string) []coverage.APIReport {
+	reports := []coverage.APIReport{}
+	for _, api := range siteData.APIs {
+		if len(apiNames) > 0 && !slices.Contains(apiNames, api.Name) {
+			continue
+		}
+		report := coverage.APIReport{Name: api.Name}
+		for _, v := range api.Versions {
+			report.Versions = append(report.Versions, coverage.VersionReport{
+				Version:  v.Version,
+				Date:     v.Info.Date,
+				Coverage: v.Coverage,
+			})
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"sort"
+	"time"
+)
+
+// 非推奨のオペレーションの状態
+const (
+	DeprecationPresent = "present"
+	DeprecationRemoved = "removed"
+)
+
+// Deprecation は非推奨または廃止予定日が宣言されたオペレーションの、非推奨化から削除までの状況です。
+type Deprecation struct {
+	// Method と Path は最後に存在したバージョンでのメソッドとパス
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	OperationID string `json:"operationId,omitempty"`
+	// DeprecatedSince は deprecated: true になったバージョン。廃止予定日だけが宣言されている場合は空です。
+	DeprecatedSince string     `json:"deprecatedSince,omitempty"`
+	DeprecatedDate  *time.Time `json:"deprecatedDate,omitempty"`
+	// Sunset は x-sunset または Sunset ヘッダーに記載された廃止予定日、SunsetSource はその取得元
+	Sunset       string `json:"sunset,omitempty"`
+	SunsetSource string `json:"sunsetSource,omitempty"`
+	// SunsetDate は Sunset を日時として解釈できた場合の値
+	SunsetDate     *time.Time `json:"sunsetDate,omitempty"`
+	StabilityLevel string     `json:"stabilityLevel,omitempty"`
+	// Status は最新のバージョンに残っていれば present、削除されていれば removed
+	Status string `json:"status"`
+	// LastVersion は最後に存在したバージョン
+	LastVersion string     `json:"lastVersion"`
+	RemovedIn   string     `json:"removedIn,omitempty"`
+	RemovedDate *time.Time `json:"removedDate,omitempty"`
+}
+
+// Overdue は廃止予定日を過ぎても最新のバージョンに残っているかを返します。
+func (d Deprecation) Overdue(now time.Time) bool {
+	return d.Status == DeprecationPresent && d.SunsetDate != nil && !d.SunsetDate.After(now)
+}
+
+// buildDeprecations は古い順に並んだバージョンをたどり、非推奨または廃止予定日が宣言されたオペレーションを集めます。
+// クライアントが呼び出すのはメソッドとパスであるため、operationId が同じでもパスが変われば元のパスは削除されたとみなします。
+// 削除されたオペレーションは削除される直前のバージョンの宣言を使います。
+func buildDeprecations(versions []Version) []Deprecation {
+	var records []*Deprecation
+	active := make(map[string]*Deprecation)
+
+	for _, version := range versions {
+		present := make(map[string]bool)
+		for _, op := range specutil.Operations(version.Doc) {
+			key := op.Key()
+			present[key] = true
+			sunset, source := op.Sunset()
+			if !op.Operation.Deprecated && sunset == "" {
+				// 非推奨が取り消された場合は一覧から外す
+				delete(active, key)
+				continue
+			}
+
+			d, ok := active[key]
+			if !ok {
+				d = &Deprecation{Method: op.Method, Path: op.Path, Status: DeprecationPresent}
+				records = append(records, d)
+				active[key] = d
+			}
+			if op.Operation.Deprecated && d.DeprecatedSince == "" {
+				date := version.Info.Date
+				d.DeprecatedSince, d.DeprecatedDate = version.Version, &date
+			}
+			d.OperationID = op.Operation.OperationID
+			d.Sunset, d.SunsetSource, d.SunsetDate = sunset, source, nil
+			if t, ok := specutil.ParseSunset(sunset); ok {
+				d.SunsetDate = &t
+			}
+			d.StabilityLevel = op.StabilityLevel()
+			d.LastVersion = version.Version
+		}
+
+		for key, d := range active {
+			if present[key] {
+				continue
+			}
+			date := version.Info.Date
+			d.Status, d.RemovedIn, d.RemovedDate = DeprecationRemoved, version.Version, &date
+			delete(active, key)
+		}
+	}
+
+	deprecations := []Deprecation{}
+	for _, d := range records {
+		// 非推奨が取り消されたものは除く
+		if d.Status == DeprecationPresent && active[d.Method+" "+d.Path] != d {
+			continue
+		}
+		deprecations = append(deprecations, *d)
+	}
+	sort.SliceStable(deprecations, func(i, j int) bool {
+		if deprecations[i].Path != deprecations[j].Path {
+			return deprecations[i].Path < deprecations[j].Path
+		}
+		return deprecations[i].Method < deprecations[j].Method
+	})
+	return deprecations
+}
+
+// OverdueDeprecation は廃止予定日を過ぎても残っている非推奨のオペレーションです。
+type OverdueDeprecation struct {
+	APIName     string
+	Deprecation Deprecation
+}
+
+// OverdueDeprecations は全ての API から、now の時点で廃止予定日を過ぎても最新のバージョンに残っているオペレーションを集めて返します。
+func OverdueDeprecations(siteData *SiteData, now time.Time) []OverdueDeprecation {
+	var overdue []OverdueDeprecation
+	for _, api := range siteData.APIs {
+		for _, d := range api.Deprecations {
+			if d.Overdue(now) {
+				overdue = append(overdue, OverdueDeprecation{APIName: api.Name, Deprecation: d})
+			}
+		}
+	}
+	return overdue
+}
+
+// String は OverdueDeprecation を人が読める形式に整形します。
+func (o OverdueDeprecation) String() string {
+	return fmt.Sprintf("%s %s %s: 廃止予定日 %s を過ぎていますが %s に残っています", o.APIName, o.Deprecation.Method, o.Deprecation.Path, o.Deprecation.Sunset, o.Deprecation.LastVersion)
+}
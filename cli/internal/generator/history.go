@@ -0,0 +1,182 @@
+package generator
+
+import (
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"time"
+)
+
+// 履歴のイベントの種類
+const (
+	EventAdded      = "added"
+	EventChanged    = "changed"
+	EventDeprecated = "deprecated"
+	EventRemoved    = "removed"
+)
+
+// History は API の全バージョンにわたるオペレーションごとの変更履歴です。
+type History struct {
+	Operations []OperationHistory `json:"operations"`
+	// ByPath は "GET /pets" 形式のキーから Operations の添字を引く索引です。
+	// 同じキーが別のオペレーションに使われたことがある場合は、最後に使ったオペレーションを指します。
+	ByPath map[string]int `json:"byPath"`
+	// ByOperationID は operationId から Operations の添字を引く索引です。
+	ByOperationID map[string]int `json:"byOperationId"`
+}
+
+// OperationHistory は1つのオペレーションの変更履歴です。
+// operationId が同じであれば、パスやメソッドが変わっても同じオペレーションとして扱います。
+type OperationHistory struct {
+	// Method と Path は最後に存在したバージョンでのメソッドとパス
+	Method      string         `json:"method"`
+	Path        string         `json:"path"`
+	OperationID string         `json:"operationId,omitempty"`
+	Events      []HistoryEvent `json:"events"`
+}
+
+// HistoryEvent はあるバージョンで起きたオペレーションの追加、変更、非推奨化、削除です。
+type HistoryEvent struct {
+	Version string    `json:"version"`
+	Date    time.Time `json:"date"`
+	Kind    string    `json:"kind"`
+	// Method と Path はそのバージョンでのメソッドとパス (削除の場合は削除前のもの)
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// PreviousVersion は変更の場合の比較元のバージョン
+	PreviousVersion string `json:"previousVersion,omitempty"`
+	// Changes と Fields は1つ前のバージョンからの変更のうち、このオペレーションに関するもの
+	Changes downloader.Diff          `json:"changes,omitempty"`
+	Fields  []downloader.FieldChange `json:"fields,omitempty"`
+}
+
+// operationState は履歴を組み立てる途中の、直前のバージョンでのオペレーションの状態です。
+type operationState struct {
+	method     string
+	path       string
+	deprecated bool
+	alive      bool
+}
+
+func (s operationState) key() string {
+	return s.method + " " + s.path
+}
+
+// buildHistory は古い順に並んだバージョンを順にたどり、オペレーションごとの変更履歴を組み立てます。
+// 追加、削除、非推奨化は各バージョンの仕様から判定し、変更は隣り合うバージョン間の差分から判定します。
+// 差分戦略により隣り合うバージョン間の差分が計算されていない場合、そのバージョンの変更は記録しません。
+func buildHistory(versions []Version) History {
+	history := History{
+		Operations:    []OperationHistory{},
+		ByPath:        make(map[string]int),
+		ByOperationID: make(map[string]int),
+	}
+	var states []operationState
+
+	for i := range versions {
+		cur := &versions[i]
+		var prev *Version
+		if i > 0 {
+			prev = &versions[i-1]
+		}
+		ops := specutil.Operations(cur.Doc)
+		matched := make(map[int]bool)
+		indexes := make([]int, len(ops))
+
+		// operationId での対応付けを優先し、パスの変更を追えるようにする
+		for j, op := range ops {
+			indexes[j] = -1
+			if idx, ok := history.ByOperationID[op.Operation.OperationID]; ok && op.Operation.OperationID != "" && !matched[idx] {
+				indexes[j] = idx
+				matched[idx] = true
+			}
+		}
+		for j, op := range ops {
+			if indexes[j] >= 0 {
+				continue
+			}
+			if idx, ok := history.ByPath[op.Key()]; ok && !matched[idx] && states[idx].key() == op.Key() {
+				indexes[j] = idx
+				matched[idx] = true
+			}
+		}
+
+		for j, op := range ops {
+			idx := indexes[j]
+			event := HistoryEvent{Version: cur.Version, Date: cur.Info.Date, Method: op.Method, Path: op.Path}
+			deprecated := op.Operation.Deprecated
+			if idx < 0 {
+				idx = len(history.Operations)
+				history.Operations = append(history.Operations, OperationHistory{})
+				states = append(states, operationState{})
+				indexes[j] = idx
+				matched[idx] = true
+			}
+			state := states[idx]
+			h := &history.Operations[idx]
+
+			switch {
+			case !state.alive:
+				event.Kind = EventAdded
+				h.Events = append(h.Events, event)
+				if deprecated {
+					event.Kind = EventDeprecated
+					h.Events = append(h.Events, event)
+				}
+			default:
+				event.Changes, event.Fields = operationChanges(prev, cur, state, op)
+				if len(event.Changes) > 0 || len(event.Fields) > 0 || state.key() != op.Key() {
+					event.Kind = EventChanged
+					event.PreviousVersion = prev.Version
+					h.Events = append(h.Events, event)
+					event.PreviousVersion, event.Changes, event.Fields = "", nil, nil
+				}
+				if deprecated && !state.deprecated {
+					event.Kind = EventDeprecated
+					h.Events = append(h.Events, event)
+				}
+			}
+
+			h.Method, h.Path = op.Method, op.Path
+			if id := op.Operation.OperationID; id != "" {
+				h.OperationID = id
+				history.ByOperationID[id] = idx
+			}
+			history.ByPath[op.Key()] = idx
+			states[idx] = operationState{method: op.Method, path: op.Path, deprecated: deprecated, alive: true}
+		}
+
+		for idx, state := range states {
+			if !state.alive || matched[idx] {
+				continue
+			}
+			h := &history.Operations[idx]
+			h.Events = append(h.Events, HistoryEvent{
+				Version: cur.Version,
+				Date:    cur.Info.Date,
+				Kind:    EventRemoved,
+				Method:  state.method,
+				Path:    state.path,
+			})
+			states[idx].alive = false
+		}
+	}
+	return history
+}
+
+// operationChanges は prev から cur への差分のうち、直前の状態が state で現在が op のオペレーションに関するものを返します。
+func operationChanges(prev *Version, cur *Version, state operationState, op specutil.Operation) (downloader.Diff, []downloader.FieldChange) {
+	if prev == nil {
+		return nil, nil
+	}
+	var changes downloader.Diff
+	for _, change := range prev.Diffs[cur.Version] {
+		if (change.Operation == state.method && change.Path == state.path) || (change.Operation == op.Method && change.Path == op.Path) {
+			changes = append(changes, change)
+		}
+	}
+	var fields []downloader.FieldChange
+	if structural, ok := prev.StructuralDiffs[cur.Version]; ok {
+		fields = structural.Operations[state.path][state.method]
+	}
+	return changes, fields
+}
@@ -4,13 +4,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/coverage"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/snippet"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
 	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
 	"sort"
-	"strings"
 )
 
 // --- 新しいデータ構造の定義 ---
@@ -22,29 +27,60 @@ type SiteData struct {
 type API struct {
 	Name     string    `json:"name"`
 	Versions []Version `json:"versions"`
+	// History は全バージョンにわたるオペレーションごとの変更履歴
+	History History `json:"history"`
+	// Deprecations は非推奨または廃止予定日が宣言されたオペレーションの一覧
+	Deprecations []Deprecation `json:"deprecations"`
 }
 
 type Version struct {
-	Version        string               `json:"version"`
-	Info           downloader.Info      `json:"info"`
-	Diffs          downloader.Diffs     `json:"diffs"`
-	Spec           interface{}          `json:"spec"` // OpenAPIの中身をそのまま格納
-	SchemaExamples map[string][]Example `json:"schemaExamples"`
+	Version string           `json:"version"`
+	Info    downloader.Info  `json:"info"`
+	Diffs   downloader.Diffs `json:"diffs"`
+	// StructuralDiffs は比較先のバージョンごとの説明や例も含む構造的な差分
+	StructuralDiffs downloader.StructuralDiffs `json:"structuralDiffs"`
+	Spec            interface{}                `json:"spec"` // OpenAPIの中身をそのまま格納
+	SchemaExamples  map[string][]Example       `json:"schemaExamples"`
+	// Snippets はパス、小文字のHTTPメソッドごとのコードサンプル
+	Snippets map[string]map[string][]snippet.Snippet `json:"snippets"`
+	// VersionCheck は1つ前のバージョンからの変更に対するバージョンの上げ方の検証結果
+	VersionCheck *diff.VersionCheck `json:"versionCheck,omitempty"`
+	// Lint は lint ステージで見つかった問題
+	Lint []lint.Problem `json:"lint,omitempty"`
+	// Coverage はドキュメントの充実度
+	Coverage coverage.Coverage `json:"coverage"`
+	// Doc は解析済みの仕様書です。JSONには出力せず、Go側のレンダラーから参照します。
+	Doc *openapi3.T `json:"-"`
 }
 
 type Example struct {
 	Description string      `json:"description"`
 	Value       interface{} `json:"value"`
 	Key         string      `json:"key"`
+	Valid       bool        `json:"valid"`
+	Errors      []string    `json:"errors,omitempty"` // スキーマとの不一致内容
 }
 
 // GenerateJSON は解析済みのドキュメントを受け取り、単一のJSONファイルとして出力します。
 func GenerateJSON(docs []*parser.APIDocument, outputDir string) error {
+	siteData, err := Aggregate(docs)
+	if err != nil {
+		return err
+	}
+	return WriteJSON(siteData, outputDir)
+}
+
+// Aggregate は解析済みのドキュメントをサイト向けのデータ構造に集約します。
+func Aggregate(docs []*parser.APIDocument) (*SiteData, error) {
 	siteData, err := aggregateDocs(docs)
 	if err != nil {
-		return fmt.Errorf("ドキュメントの集約に失敗しました: %w", err)
+		return nil, fmt.Errorf("ドキュメントの集約に失敗しました: %w", err)
 	}
+	return siteData, nil
+}
 
+// WriteJSON は集約済みのデータを outputDir/data/api-data.json に書き出します。
+func WriteJSON(siteData *SiteData, outputDir string) error {
 	jsonData, err := json.MarshalIndent(siteData, "", "  ")
 	if err != nil {
 		return fmt.Errorf("JSONへのマーシャリングに失敗しました: %w", err)
@@ -80,21 +116,29 @@ func aggregateDocs(docs []*parser.APIDocument) (*SiteData, error) {
 		allExamples := extractSchemaData(doc.Doc)
 
 		version := Version{
-			Version:        doc.Version,
-			Spec:           specData,
-			Info:           doc.Info,
-			Diffs:          doc.Diffs,
-			SchemaExamples: allExamples,
+			Version:         doc.Version,
+			Spec:            specData,
+			Info:            doc.Info,
+			Diffs:           doc.Diffs,
+			StructuralDiffs: doc.StructuralDiffs,
+			SchemaExamples:  allExamples,
+			Snippets:        snippet.Generate(doc.Doc),
+			Lint:            doc.Lint,
+			Coverage:        coverage.Compute(doc.Doc),
+			Doc:             doc.Doc,
 		}
 		apiMap[doc.APIName] = append(apiMap[doc.APIName], version)
 	}
 
 	var siteApis []API
 	for apiName, versions := range apiMap {
-		sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+		sort.Slice(versions, func(i, j int) bool { return semver.Compare(versions[i].Version, versions[j].Version) < 0 })
+		checkVersions(versions)
 		siteApis = append(siteApis, API{
-			Name:     apiName,
-			Versions: versions,
+			Name:         apiName,
+			Versions:     versions,
+			History:      buildHistory(versions),
+			Deprecations: buildDeprecations(versions),
 		})
 	}
 	sort.Slice(siteApis, func(i, j int) bool { return siteApis[i].Name < siteApis[j].Name })
@@ -118,21 +162,22 @@ func newSchemaDataCollector() *schemaDataCollector {
 }
 
 // addExamples はExample/Examplesを追加します。
-func (c *schemaDataCollector) addExamples(schemaName string, schemaKey string, example Example, examples map[string]*openapi3.ExampleRef) {
+// 収集した各Exampleは紐づくスキーマに対して opts の設定で検証されます。
+func (c *schemaDataCollector) addExamples(schemaName string, schemaKey string, schema *openapi3.Schema, example Example, examples map[string]*openapi3.ExampleRef, opts ...openapi3.SchemaValidationOption) {
 	if schemaName == "" {
 		return
 	}
 	var collected []Example
 	if example.Value != nil {
-		collected = append(collected, example)
+		collected = append(collected, validateExample(schema, example, opts...))
 	}
 	for _, exRef := range examples {
 		if exRef != nil && exRef.Value != nil && exRef.Value.Value != nil {
-			collected = append(collected, Example{
+			collected = append(collected, validateExample(schema, Example{
 				Description: exRef.Value.Description,
 				Value:       exRef.Value.Value,
 				Key:         schemaKey,
-			})
+			}, opts...))
 		}
 	}
 	if len(collected) > 0 {
@@ -140,14 +185,6 @@ func (c *schemaDataCollector) addExamples(schemaName string, schemaKey string, e
 	}
 }
 
-// getSchemaNameFromRef は $ref 文字列からスキーマ名を抽出します (例: "#/components/schemas/User" -> "User")
-func getSchemaNameFromRef(ref string) string {
-	if !strings.HasPrefix(ref, "#/components/schemas/") {
-		return ""
-	}
-	return strings.TrimPrefix(ref, "#/components/schemas/")
-}
-
 // extractSchemaData は仕様書全体を探索し、スキーマ名に紐づくデータを収集します。
 func extractSchemaData(doc *openapi3.T) map[string][]Example {
 	collector := newSchemaDataCollector()
@@ -156,7 +193,7 @@ func extractSchemaData(doc *openapi3.T) map[string][]Example {
 	if doc.Components != nil && doc.Components.Schemas != nil {
 		for name, schemaRef := range doc.Components.Schemas {
 			if schemaRef != nil && schemaRef.Value != nil {
-				collector.addExamples(name, "",
+				collector.addExamples(name, "", schemaRef.Value,
 					Example{
 						Description: schemaRef.Value.Description,
 						Value:       schemaRef.Value.Example,
@@ -189,13 +226,13 @@ func extractSchemaData(doc *openapi3.T) map[string][]Example {
 						continue
 					}
 					if paramRef.Value.Schema != nil {
-						schemaName := getSchemaNameFromRef(paramRef.Value.Schema.Ref)
+						schemaName := specutil.SchemaNameFromRef(paramRef.Value.Schema.Ref)
 						key := fmt.Sprintf("components.paths.%s.%s.parameters.%d.%s", path, operation, parameter, schemaName)
-						collector.addExamples(schemaName, key, Example{
+						collector.addExamples(schemaName, key, paramRef.Value.Schema.Value, Example{
 							Description: paramRef.Value.Description,
 							Value:       paramRef.Value.Example,
 							Key:         key,
-						}, paramRef.Value.Examples)
+						}, paramRef.Value.Examples, openapi3.VisitAsRequest())
 					}
 				}
 
@@ -203,7 +240,7 @@ func extractSchemaData(doc *openapi3.T) map[string][]Example {
 				if op.RequestBody != nil && op.RequestBody.Value != nil {
 					//collector.addDescriptionToContent(op.RequestBody.Value.Content, op.RequestBody.Value.Description)
 					key := fmt.Sprintf("components.paths.%s.%s.requestBody", path, operation)
-					collector.addExampleToContent(key, op.RequestBody.Value.Content)
+					collector.addExampleToContent(key, op.RequestBody.Value.Content, openapi3.VisitAsRequest())
 				}
 
 				// Responses
@@ -212,7 +249,7 @@ func extractSchemaData(doc *openapi3.T) map[string][]Example {
 						if respRef != nil && respRef.Value != nil {
 							//collector.addDescriptionToContent(respRef.Value.Content, respRef.Value.Description)
 							key := fmt.Sprintf("components.paths.%s.%s.response.%s", path, operation, code)
-							collector.addExampleToContent(key, respRef.Value.Content)
+							collector.addExampleToContent(key, respRef.Value.Content, openapi3.VisitAsResponse())
 						}
 					}
 				}
@@ -230,26 +267,27 @@ func (c *schemaDataCollector) addDescriptionToContent(content openapi3.Content)
 	}
 	for _, mediaType := range content {
 		if mediaType != nil && mediaType.Schema != nil {
-			_ = getSchemaNameFromRef(mediaType.Schema.Ref)
+			_ = specutil.SchemaNameFromRef(mediaType.Schema.Ref)
 			//c.addDescription(schemaName, description)
 		}
 	}
 }
 
 // addExampleToContent はContentオブジェクト内のスキーマにExampleを追加します。
-func (c *schemaDataCollector) addExampleToContent(key string, content openapi3.Content) {
+// opts にはリクエストかレスポンスかに応じた検証の設定を渡します。
+func (c *schemaDataCollector) addExampleToContent(key string, content openapi3.Content, opts ...openapi3.SchemaValidationOption) {
 	if content == nil {
 		return
 	}
 	for mimeType, mediaType := range content {
 		if mediaType != nil && mediaType.Schema != nil {
-			schemaName := getSchemaNameFromRef(mediaType.Schema.Ref)
+			schemaName := specutil.SchemaNameFromRef(mediaType.Schema.Ref)
 			key := fmt.Sprintf("%s.%s.%s", key, mimeType, schemaName)
-			c.addExamples(schemaName, key, Example{
+			c.addExamples(schemaName, key, mediaType.Schema.Value, Example{
 				Description: mimeType,
 				Value:       mediaType.Example,
 				Key:         key,
-			}, mediaType.Examples)
+			}, mediaType.Examples, opts...)
 		}
 	}
 }
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+)
+
+// LintFailure は最新バージョンで失敗とみなすレベル以上の lint の問題です。
+type LintFailure struct {
+	APIName string
+	Version string
+	Problem lint.Problem
+}
+
+// LintDocs は全てのドキュメントを ruleset で検査して doc.Lint に記録し、見つかった問題の数を返します。
+func LintDocs(docs []*parser.APIDocument, ruleset *lint.Ruleset) int {
+	count := 0
+	for _, doc := range docs {
+		doc.Lint = ruleset.Lint(doc.Doc)
+		count += len(doc.Lint)
+	}
+	return count
+}
+
+// LintFailuresInLatest は API ごとの最新バージョンから、level 以上の lint の問題を集めて返します。
+// 公開済みの古いバージョンは修正できないため対象にしません。
+func LintFailuresInLatest(docs []*parser.APIDocument, level int) []LintFailure {
+	latest := make(map[string]*parser.APIDocument)
+	var names []string
+	for _, doc := range docs {
+		cur, ok := latest[doc.APIName]
+		if !ok {
+			names = append(names, doc.APIName)
+		}
+		if !ok || semver.Compare(cur.Version, doc.Version) < 0 {
+			latest[doc.APIName] = doc
+		}
+	}
+
+	var failures []LintFailure
+	for _, name := range names {
+		doc := latest[name]
+		for _, p := range doc.Lint {
+			if p.Level >= level {
+				failures = append(failures, LintFailure{APIName: doc.APIName, Version: doc.Version, Problem: p})
+			}
+		}
+	}
+	return failures
+}
+
+// String は LintFailure を人が読める形式に整形します。
+func (f LintFailure) String() string {
+	return fmt.Sprintf("%s %s: %s", f.APIName, f.Version, f.Problem)
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strings"
+)
+
+// InvalidExample はスキーマに適合しなかったExampleの情報です。
+type InvalidExample struct {
+	APIName string
+	Version string
+	Schema  string
+	Example Example
+}
+
+// validateExample はExampleの値をスキーマに対して検証し、結果を記録したExampleを返します。
+// リクエストとレスポンスの例では、readOnly と writeOnly のプロパティを正しく扱えるよう
+// openapi3.VisitAsRequest() か openapi3.VisitAsResponse() を opts に渡します。
+func validateExample(schema *openapi3.Schema, example Example, opts ...openapi3.SchemaValidationOption) Example {
+	example.Valid = true
+	example.Errors = nil
+	if schema == nil {
+		return example
+	}
+
+	err := schema.VisitJSON(example.Value, append([]openapi3.SchemaValidationOption{openapi3.MultiErrors()}, opts...)...)
+	if err == nil {
+		return example
+	}
+
+	example.Valid = false
+	var multi openapi3.MultiError
+	if errors.As(err, &multi) {
+		for _, e := range multi {
+			example.Errors = append(example.Errors, formatSchemaError(e))
+		}
+	} else {
+		example.Errors = append(example.Errors, formatSchemaError(err))
+	}
+	return example
+}
+
+// formatSchemaError はスキーマ検証エラーを "/path: 理由" の形式に整形します。
+func formatSchemaError(err error) string {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err.Error()
+	}
+	return fmt.Sprintf("/%s: %s", strings.Join(schemaErr.JSONPointer(), "/"), schemaErr.Reason)
+}
+
+// InvalidExamplesInLatest は各APIの最新バージョンに含まれる不正なExampleを返します。
+func InvalidExamplesInLatest(siteData *SiteData) []InvalidExample {
+	var invalid []InvalidExample
+	for _, api := range siteData.APIs {
+		if len(api.Versions) == 0 {
+			continue
+		}
+		latest := api.Versions[len(api.Versions)-1]
+		for schemaName, examples := range latest.SchemaExamples {
+			for _, example := range examples {
+				if example.Valid {
+					continue
+				}
+				invalid = append(invalid, InvalidExample{
+					APIName: api.Name,
+					Version: latest.Version,
+					Schema:  schemaName,
+					Example: example,
+				})
+			}
+		}
+	}
+	sort.Slice(invalid, func(i, j int) bool {
+		if invalid[i].APIName != invalid[j].APIName {
+			return invalid[i].APIName < invalid[j].APIName
+		}
+		if invalid[i].Schema != invalid[j].Schema {
+			return invalid[i].Schema < invalid[j].Schema
+		}
+		return invalid[i].Example.Key < invalid[j].Example.Key
+	})
+	return invalid
+}
+
+// String は InvalidExample を人が読める形式に整形します。
+func (e InvalidExample) String() string {
+	return fmt.Sprintf("%s %s: %s (%s): %v", e.APIName, e.Version, e.Schema, e.Example.Key, e.Example.Errors)
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"testing"
+)
+
+const readWriteOnlySpec = `
+openapi: 3.0.3
+info: {title: t, version: 1.0.0}
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+            example: {name: a, password: secret}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              example: {id: 1, name: a}
+  /accounts:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/User'}
+            examples:
+              request: {value: {name: b, password: secret}}
+      responses:
+        "201":
+          description: created
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/User'}
+              examples:
+                response: {value: {id: 2, name: b}}
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name, password]
+      properties:
+        id: {type: integer, readOnly: true}
+        name: {type: string}
+        password: {type: string, writeOnly: true}
+`
+
+func TestExtractSchemaDataValidatesReadWriteOnly(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData([]byte(readWriteOnlySpec))
+	if err != nil {
+		t.Fatalf("仕様の読み込みに失敗しました: %v", err)
+	}
+	examples := extractSchemaData(doc)["User"]
+	// example と examples の両方をリクエストとレスポンスで2件ずつ
+	if len(examples) != 4 {
+		t.Fatalf("User の例が %d 件です。4 件を期待しました: %+v", len(examples), examples)
+	}
+	for _, example := range examples {
+		if !example.Valid {
+			t.Errorf("%s: readOnly/writeOnly のプロパティを省いた例が不正と判定されました: %v", example.Key, example.Errors)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+)
+
+// VersionMismatch は変更の内容に対してバージョンの上げ方が足りないバージョンの情報です。
+type VersionMismatch struct {
+	APIName string
+	Version string
+	Check   diff.VersionCheck
+}
+
+// checkVersions は古い順に並んだバージョンについて、1つ前のバージョンからの変更に対して
+// info.version の上げ方が十分かを検証し、結果を VersionCheck に記録します。
+// 1つ前のバージョンとの差分が計算されていない場合は検証しません。
+func checkVersions(versions []Version) {
+	for i := 1; i < len(versions); i++ {
+		prev, cur := &versions[i-1], &versions[i]
+		changes, ok := prev.Diffs[cur.Version]
+		if !ok {
+			continue
+		}
+		if check, ok := diff.CheckVersion(infoVersion(prev), infoVersion(cur), changes, prev.StructuralDiffs[cur.Version]); ok {
+			cur.VersionCheck = &check
+		}
+	}
+}
+
+// infoVersion は仕様書の info.version を返します。記載がなければディレクトリ名のバージョンを返します。
+func infoVersion(v *Version) string {
+	if v.Doc != nil && v.Doc.Info != nil && v.Doc.Info.Version != "" {
+		return v.Doc.Info.Version
+	}
+	return v.Version
+}
+
+// VersionMismatches は全ての API からバージョンの上げ方が足りないバージョンを集めて返します。
+func VersionMismatches(siteData *SiteData) []VersionMismatch {
+	var mismatches []VersionMismatch
+	for _, api := range siteData.APIs {
+		for _, version := range api.Versions {
+			if version.VersionCheck != nil && !version.VersionCheck.OK {
+				mismatches = append(mismatches, VersionMismatch{APIName: api.Name, Version: version.Version, Check: *version.VersionCheck})
+			}
+		}
+	}
+	return mismatches
+}
+
+// String は VersionMismatch を人が読める形式に整形します。
+func (m VersionMismatch) String() string {
+	return fmt.Sprintf("%s %s: %s → %s は %s の変更が必要ですが %s になっています", m.APIName, m.Version, m.Check.From, m.Check.To, m.Check.Required, m.Check.Actual)
+}
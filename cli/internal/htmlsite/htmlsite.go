@@ -0,0 +1,217 @@
+package htmlsite
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+//go:embed assets
+var assetFS embed.FS
+
+// pageTemplates はページごとのテンプレートファイルです。各ページは layout.html と組み合わせて使います。
+var pageTemplates = []string{"index", "version", "endpoint", "schema", "compare"}
+
+// page はテンプレートに渡すデータです。
+type page struct {
+	Title   string
+	Root    string // サイトルートへの相対パス
+	Site    *generator.SiteData
+	API     *generator.API
+	Version *generator.Version
+
+	Groups     []tagGroup
+	Schemas    []schemaView
+	Coverage   []coverageView
+	Path       string
+	Operations []operationView
+	Schema     schemaView
+
+	OldVersion string
+	Changes    []downloader.Change
+	Reversible bool // 逆方向の比較ページが存在するか
+	// OperationFields と SchemaFields は比較ページに表示する項目の変更
+	OperationFields []fieldChangesView
+	SchemaFields    []fieldChangesView
+}
+
+// siteWriter はテンプレートを使ってHTMLファイルを書き出します。
+type siteWriter struct {
+	templates map[string]*template.Template
+	outputDir string
+	site      *generator.SiteData
+}
+
+// Write は SiteData から静的なHTMLサイトを outputDir/html に生成し、トップページのパスを返します。
+func Write(siteData *generator.SiteData, outputDir string) (string, error) {
+	htmlDir := filepath.Join(outputDir, "html")
+	w, err := newSiteWriter(siteData, htmlDir)
+	if err != nil {
+		return "", err
+	}
+	if err := w.writeAssets(); err != nil {
+		return "", err
+	}
+
+	if err := w.render("index", "index.html", page{Title: "API一覧"}); err != nil {
+		return "", err
+	}
+	for i := range siteData.APIs {
+		api := &siteData.APIs[i]
+		for j := range api.Versions {
+			if err := w.writeVersion(api, &api.Versions[j]); err != nil {
+				return "", err
+			}
+		}
+	}
+	return filepath.Join(htmlDir, "index.html"), nil
+}
+
+func newSiteWriter(siteData *generator.SiteData, outputDir string) (*siteWriter, error) {
+	funcs := template.FuncMap{
+		"lower":       strings.ToLower,
+		"versionURL":  versionURL,
+		"compareURL":  compareURL,
+		"endpointURL": endpointURL,
+		"schemaURL":   schemaURL,
+		"json":        prettyJSON,
+		"typeArgs": func(root string, t typeView) map[string]interface{} {
+			return map[string]interface{}{"Root": root, "Type": t}
+		},
+		// 廃止予定日を過ぎたかはサイトを生成した時点で判定する
+		"overdue": func(d generator.Deprecation) bool { return d.Overdue(time.Now()) },
+		"latest": func(api *generator.API) *generator.Version {
+			if len(api.Versions) == 0 {
+				return nil
+			}
+			return &api.Versions[len(api.Versions)-1]
+		},
+		"levelName":  func(c downloader.Change) string { return c.LevelName() },
+		"kindLabel":  kindLabel,
+		"eventLabel": eventLabel,
+	}
+
+	templates := make(map[string]*template.Template)
+	for _, name := range pageTemplates {
+		t, err := template.New("layout.html").Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("テンプレート '%s' の読み込みに失敗しました: %w", name, err)
+		}
+		templates[name] = t
+	}
+	return &siteWriter{templates: templates, outputDir: outputDir, site: siteData}, nil
+}
+
+func (w *siteWriter) writeVersion(api *generator.API, version *generator.Version) error {
+	if version.Doc == nil {
+		return nil
+	}
+	v := views{api: api, version: version}
+	base := page{API: api, Version: version}
+
+	p := base
+	p.Title = fmt.Sprintf("%s %s", api.Name, version.Version)
+	p.Groups = v.tagGroups()
+	p.Schemas = v.schemas()
+	p.Coverage = v.coverage()
+	if err := w.render("version", versionURL(api.Name, version.Version), p); err != nil {
+		return err
+	}
+
+	for _, group := range p.Groups {
+		for _, path := range group.Paths {
+			ep := base
+			ep.Title = fmt.Sprintf("%s - %s %s", path.Path, api.Name, version.Version)
+			ep.Path = path.Path
+			ep.Operations = v.operations(path.Path)
+			if err := w.render("endpoint", path.Link, ep); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, schema := range p.Schemas {
+		sp := base
+		sp.Title = fmt.Sprintf("%s - %s %s", schema.Name, api.Name, version.Version)
+		sp.Schema = schema
+		if err := w.render("schema", schemaURL(api.Name, version.Version, schema.Name), sp); err != nil {
+			return err
+		}
+	}
+
+	oldVersions := make([]string, 0, len(version.Diffs))
+	for oldVersion := range version.Diffs {
+		oldVersions = append(oldVersions, oldVersion)
+	}
+	sort.Strings(oldVersions)
+	for _, oldVersion := range oldVersions {
+		cp := base
+		cp.Title = fmt.Sprintf("%sの%sと%sの比較", api.Name, version.Version, oldVersion)
+		cp.OldVersion = oldVersion
+		cp.Changes = version.Diffs[oldVersion]
+		cp.Reversible = hasDiff(api, oldVersion, version.Version)
+		cp.OperationFields, cp.SchemaFields = fieldChangesViews(version.StructuralDiffs[oldVersion])
+		if err := w.render("compare", compareURL(api.Name, version.Version, oldVersion), cp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasDiff は API の newVersion に oldVersion との差分が含まれているかを返します。
+func hasDiff(api *generator.API, newVersion string, oldVersion string) bool {
+	for _, v := range api.Versions {
+		if v.Version == newVersion {
+			_, ok := v.Diffs[oldVersion]
+			return ok
+		}
+	}
+	return false
+}
+
+// render はテンプレートを実行し、サイトルートからの相対パス rel に書き出します。
+func (w *siteWriter) render(name string, rel string, p page) error {
+	p.Site = w.site
+	p.Root = strings.Repeat("../", strings.Count(rel, "/"))
+
+	var buf bytes.Buffer
+	if err := w.templates[name].Execute(&buf, p); err != nil {
+		return fmt.Errorf("ページ '%s' のレンダリングに失敗しました: %w", rel, err)
+	}
+
+	outputPath := filepath.Join(w.outputDir, filepath.FromSlash(unescapePath(rel)))
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("ディレクトリ '%s' の作成に失敗しました: %w", filepath.Dir(outputPath), err)
+	}
+	return os.WriteFile(outputPath, buf.Bytes(), 0644)
+}
+
+// writeAssets は埋め込まれたCSSなどの静的ファイルを書き出します。
+func (w *siteWriter) writeAssets() error {
+	return fs.WalkDir(assetFS, "assets", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := assetFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		outputPath := filepath.Join(w.outputDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(outputPath, data, 0644)
+	})
+}
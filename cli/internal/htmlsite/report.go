@@ -0,0 +1,45 @@
+package htmlsite
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// WriteChanges は変更の一覧を、スタイルを埋め込んだ1枚のHTMLとして書き出します。
+func WriteChanges(w io.Writer, title string, changes []downloader.Change) error {
+	style, err := Stylesheet()
+	if err != nil {
+		return err
+	}
+
+	funcs := template.FuncMap{
+		"lower":     strings.ToLower,
+		"levelName": func(c downloader.Change) string { return c.LevelName() },
+	}
+	t, err := template.New("report.html").Funcs(funcs).ParseFS(templateFS, "templates/report.html")
+	if err != nil {
+		return fmt.Errorf("テンプレート 'report' の読み込みに失敗しました: %w", err)
+	}
+
+	data := struct {
+		Title   string
+		Style   template.CSS
+		Changes []downloader.Change
+	}{Title: title, Style: style, Changes: changes}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("HTMLの書き出しに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Stylesheet は生成するサイトのスタイルシートを、1枚のHTMLに埋め込める形で返します。
+func Stylesheet() (template.CSS, error) {
+	style, err := assetFS.ReadFile("assets/style.css")
+	if err != nil {
+		return "", fmt.Errorf("スタイルシートの読み込みに失敗しました: %w", err)
+	}
+	return template.CSS(style), nil
+}
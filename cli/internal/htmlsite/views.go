@@ -0,0 +1,460 @@
+package htmlsite
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/coverage"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/snippet"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// untaggedGroup はタグが付いていないエンドポイントの見出しです。
+const untaggedGroup = "その他"
+
+type operationView struct {
+	Method      string
+	Path        string
+	Anchor      string
+	Summary     string
+	Description string
+	OperationID string
+	Deprecated  bool
+	Parameters  []parameterView
+	RequestBody []contentView
+	Responses   []responseView
+	Snippets    []snippet.Snippet
+	History     []generator.HistoryEvent
+}
+
+type parameterView struct {
+	Name        string
+	In          string
+	Type        typeView
+	Required    bool
+	Description string
+}
+
+type typeView struct {
+	Name string
+	Link string // スキーマページへのサイトルートからのパス (参照スキーマでなければ空)
+}
+
+type contentView struct {
+	MimeType string
+	Type     typeView
+	Example  string
+}
+
+type responseView struct {
+	Code        string
+	Description string
+	Contents    []contentView
+}
+
+type tagGroup struct {
+	Name        string
+	Description string
+	Paths       []pathView
+}
+
+type pathView struct {
+	Path    string
+	Link    string
+	Methods []string
+	Summary string
+}
+
+type schemaView struct {
+	Name        string
+	Description string
+	Type        typeView
+	Properties  []propertyView
+	Enum        []string
+	Example     string
+	Examples    []generator.Example
+	Raw         string
+}
+
+type propertyView struct {
+	Name        string
+	Type        typeView
+	Required    bool
+	Description string
+}
+
+// coverageView はドキュメントの充実度の1つの指標です。
+type coverageView struct {
+	Label   string
+	Covered int
+	Total   int
+	Percent string // 対象がなければ空
+	Trend   string // 1つ前のバージョンからの増減 (変化がなければ空)
+}
+
+// fieldChangesView は1つのオペレーションかスキーマの項目の変更です。
+type fieldChangesView struct {
+	Method  string // オペレーションの場合の HTTP メソッド
+	Name    string // オペレーションのパスかスキーマ名
+	Changes []fieldChangeView
+}
+
+type fieldChangeView struct {
+	Path string // 項目の経路を " › " でつないだもの
+	Kind string
+	From string
+	To   string
+
+	RenamedFrom string
+	RenamedTo   string
+}
+
+// fieldChangesViews は構造的な差分をオペレーションとスキーマごとに並べ替えて返します。
+func fieldChangesViews(d downloader.StructuralDiff) (operations []fieldChangesView, schemas []fieldChangesView) {
+	paths := make([]string, 0, len(d.Operations))
+	for path := range d.Operations {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		methods := make([]string, 0, len(d.Operations[path]))
+		for method := range d.Operations[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+		for _, method := range methods {
+			operations = append(operations, fieldChangesView{Method: method, Name: path, Changes: fieldChangeViews(d.Operations[path][method])})
+		}
+	}
+
+	names := make([]string, 0, len(d.Schemas))
+	for name := range d.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schemas = append(schemas, fieldChangesView{Name: name, Changes: fieldChangeViews(d.Schemas[name])})
+	}
+	return operations, schemas
+}
+
+func fieldChangeViews(changes []downloader.FieldChange) []fieldChangeView {
+	views := make([]fieldChangeView, 0, len(changes))
+	for _, change := range changes {
+		views = append(views, fieldChangeView{
+			Path: strings.Join(change.Path, " › "),
+			Kind: change.Kind,
+			From: prettyJSON(change.From),
+			To:   prettyJSON(change.To),
+
+			RenamedFrom: change.RenamedFrom,
+			RenamedTo:   change.RenamedTo,
+		})
+	}
+	return views
+}
+
+// kindLabel は項目の変更の種類の表示名を返します。
+func kindLabel(kind string) string {
+	switch kind {
+	case "added":
+		return "追加"
+	case "deleted":
+		return "削除"
+	default:
+		return "変更"
+	}
+}
+
+// eventLabel は変更履歴のイベントの種類の表示名を返します。
+func eventLabel(kind string) string {
+	switch kind {
+	case generator.EventAdded:
+		return "追加"
+	case generator.EventDeprecated:
+		return "非推奨化"
+	case generator.EventRemoved:
+		return "削除"
+	default:
+		return "変更"
+	}
+}
+
+// versionURL はバージョンページのサイトルートからのパスを返します。
+func versionURL(apiName string, version string) string {
+	return fmt.Sprintf("docs/%s/%s/index.html", segment(apiName), segment(version))
+}
+
+// endpointURL はエンドポイントページのサイトルートからのパスを返します。
+// パスはNext.jsサイトと同じく先頭のスラッシュを除いてbase64urlでエンコードします。
+func endpointURL(apiName string, version string, path string) string {
+	return fmt.Sprintf("docs/%s/%s/endpoints/%s/index.html", segment(apiName), segment(version), encodePath(path))
+}
+
+// schemaURL はスキーマページのサイトルートからのパスを返します。
+func schemaURL(apiName string, version string, name string) string {
+	return fmt.Sprintf("docs/%s/%s/schemas/%s/index.html", segment(apiName), segment(version), segment(name))
+}
+
+// compareURL は比較ページのサイトルートからのパスを返します。
+func compareURL(apiName string, newVersion string, oldVersion string) string {
+	return fmt.Sprintf("compare/%s/%s/%s/index.html", segment(apiName), segment(newVersion), segment(oldVersion))
+}
+
+// segment は名前をリンクのパスの1要素としてエスケープします。
+// ファイル名にすると別のディレクトリを指してしまう名前 ("/" や "\" を含む名前、"." と "..") は
+// エスケープした形のままファイル名にするよう、さらにもう1度エスケープします。"%" を含む名前も、
+// エスケープした形のファイル名と衝突しないよう同じく2度エスケープします。
+func segment(name string) string {
+	if name == "." || name == ".." {
+		return url.PathEscape(strings.ReplaceAll(name, ".", "%2E"))
+	}
+	if strings.ContainsAny(name, "/\\%") {
+		return url.PathEscape(url.PathEscape(name))
+	}
+	return url.PathEscape(name)
+}
+
+// unescapePath はサイトルートからのパスをファイルパスとして使えるようにアンエスケープします。
+// segment でエスケープした要素は、"/" などを含む名前もエスケープした形のファイル名になります。
+func unescapePath(rel string) string {
+	unescaped, err := url.PathUnescape(rel)
+	if err != nil {
+		return rel
+	}
+	return unescaped
+}
+
+func encodePath(path string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.TrimPrefix(path, "/")))
+}
+
+// views はレンダリング中のAPIバージョンに対するビューモデルを組み立てます。
+type views struct {
+	api     *generator.API
+	version *generator.Version
+}
+
+func (v views) typeOf(ref *openapi3.SchemaRef) typeView {
+	name, schemaName := specutil.SchemaType(ref)
+	t := typeView{Name: name}
+	if schemaName != "" {
+		t.Link = schemaURL(v.api.Name, v.version.Version, schemaName)
+	}
+	return t
+}
+
+func (v views) tagGroups() []tagGroup {
+	var groups []tagGroup
+	index := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, op := range specutil.Operations(v.version.Doc) {
+		tag := untaggedGroup
+		if len(op.Operation.Tags) > 0 {
+			tag = op.Operation.Tags[0]
+		}
+		if _, ok := index[tag]; !ok {
+			index[tag] = len(groups)
+			group := tagGroup{Name: tag}
+			if t := v.version.Doc.Tags.Get(tag); t != nil {
+				group.Description = t.Description
+			}
+			groups = append(groups, group)
+		}
+		g := &groups[index[tag]]
+		key := tag + " " + op.Path
+		if !seen[key] {
+			seen[key] = true
+			g.Paths = append(g.Paths, pathView{
+				Path:    op.Path,
+				Link:    endpointURL(v.api.Name, v.version.Version, op.Path),
+				Summary: op.Operation.Summary,
+			})
+		}
+		g.Paths[len(g.Paths)-1].Methods = append(g.Paths[len(g.Paths)-1].Methods, op.Method)
+	}
+	return groups
+}
+
+func (v views) operations(path string) []operationView {
+	var ops []operationView
+	for _, op := range specutil.Operations(v.version.Doc) {
+		if op.Path != path {
+			continue
+		}
+		view := operationView{
+			Method:      op.Method,
+			Path:        op.Path,
+			Anchor:      specutil.OperationAnchor(op.Method, op.Path),
+			Summary:     op.Operation.Summary,
+			Description: op.Operation.Description,
+			OperationID: op.Operation.OperationID,
+			Deprecated:  op.Operation.Deprecated,
+			Snippets:    v.version.Snippets[op.Path][strings.ToLower(op.Method)],
+			History:     v.history(op),
+		}
+		for _, ref := range op.Parameters() {
+			view.Parameters = append(view.Parameters, parameterView{
+				Name:        ref.Value.Name,
+				In:          ref.Value.In,
+				Type:        v.typeOf(ref.Value.Schema),
+				Required:    ref.Value.Required,
+				Description: ref.Value.Description,
+			})
+		}
+		if op.Operation.RequestBody != nil && op.Operation.RequestBody.Value != nil {
+			view.RequestBody = v.contents(op.Operation.RequestBody.Value.Content, true)
+		}
+		if op.Operation.Responses != nil {
+			codes := make([]string, 0, op.Operation.Responses.Len())
+			for code := range op.Operation.Responses.Map() {
+				codes = append(codes, code)
+			}
+			sort.Strings(codes)
+			for _, code := range codes {
+				ref := op.Operation.Responses.Value(code)
+				if ref == nil || ref.Value == nil {
+					continue
+				}
+				response := responseView{Code: code, Contents: v.contents(ref.Value.Content, false)}
+				if ref.Value.Description != nil {
+					response.Description = *ref.Value.Description
+				}
+				view.Responses = append(view.Responses, response)
+			}
+		}
+		ops = append(ops, view)
+	}
+	return ops
+}
+
+// coverage はドキュメントの充実度の指標を、1つ前のバージョンからの増減とともに返します。
+func (v views) coverage() []coverageView {
+	var previous []coverage.NamedMetric
+	for i := range v.api.Versions {
+		if &v.api.Versions[i] == v.version && i > 0 {
+			previous = v.api.Versions[i-1].Coverage.Metrics()
+		}
+	}
+	var views []coverageView
+	for i, m := range v.version.Coverage.Metrics() {
+		view := coverageView{Label: m.Label, Covered: m.Metric.Covered, Total: m.Metric.Total}
+		percent, ok := m.Metric.Percent()
+		if ok {
+			view.Percent = fmt.Sprintf("%.1f%%", percent)
+		}
+		if ok && previous != nil {
+			if before, ok := previous[i].Metric.Percent(); ok && math.Abs(percent-before) >= 0.05 {
+				view.Trend = fmt.Sprintf("%+.1f", percent-before)
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// history はオペレーションの変更履歴を operationId、メソッドとパスの順に探して返します。
+func (v views) history(op specutil.Operation) []generator.HistoryEvent {
+	h := v.api.History
+	if idx, ok := h.ByOperationID[op.Operation.OperationID]; ok && op.Operation.OperationID != "" {
+		return h.Operations[idx].Events
+	}
+	if idx, ok := h.ByPath[op.Key()]; ok {
+		return h.Operations[idx].Events
+	}
+	return nil
+}
+
+// contents はメディアタイプごとの型と例示値をまとめます。
+// sample が true の場合、例示値がなければスキーマからサンプル値を組み立てます。
+func (v views) contents(content openapi3.Content, sample bool) []contentView {
+	mimeTypes := make([]string, 0, len(content))
+	for mimeType, mediaType := range content {
+		if mediaType != nil {
+			mimeTypes = append(mimeTypes, mimeType)
+		}
+	}
+	sort.Strings(mimeTypes)
+
+	var views []contentView
+	for _, mimeType := range mimeTypes {
+		mediaType := content[mimeType]
+		view := contentView{MimeType: mimeType, Type: v.typeOf(mediaType.Schema)}
+		if sample || mediaType.Example != nil || len(mediaType.Examples) > 0 {
+			view.Example = prettyJSON(specutil.MediaTypeExample(mediaType))
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+func (v views) schemas() []schemaView {
+	var schemas []schemaView
+	for _, name := range specutil.SchemaNames(v.version.Doc) {
+		if schema, ok := v.schema(name); ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas
+}
+
+func (v views) schema(name string) (schemaView, bool) {
+	ref := v.version.Doc.Components.Schemas[name]
+	if ref == nil || ref.Value == nil {
+		return schemaView{}, false
+	}
+	schema := ref.Value
+	view := schemaView{
+		Name:        name,
+		Description: schema.Description,
+		Type:        v.typeOf(&openapi3.SchemaRef{Value: schema}),
+		Examples:    v.version.SchemaExamples[name],
+		Raw:         prettyJSON(schema),
+	}
+	if schema.Example != nil {
+		view.Example = prettyJSON(schema.Example)
+	}
+	for _, value := range schema.Enum {
+		view.Enum = append(view.Enum, fmt.Sprint(value))
+	}
+
+	required := make(map[string]bool)
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+	for _, prop := range props {
+		propRef := schema.Properties[prop]
+		property := propertyView{Name: prop, Type: v.typeOf(propRef), Required: required[prop]}
+		if propRef != nil && propRef.Value != nil {
+			property.Description = propRef.Value.Description
+		}
+		view.Properties = append(view.Properties, property)
+	}
+	return view, true
+}
+
+func prettyJSON(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
@@ -0,0 +1,38 @@
+package htmlsite
+
+import (
+	"path"
+	"testing"
+)
+
+func TestSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		file string
+	}{
+		{"1.0.0", "1.0.0", "1.0.0"},
+		{"ペット", "%E3%83%9A%E3%83%83%E3%83%88", "ペット"},
+		{"a b", "a%20b", "a b"},
+		{"a/b", "a%252Fb", "a%2Fb"},
+		{`a\b`, "a%255Cb", "a%5Cb"},
+		{"a%2Fb", "a%25252Fb", "a%252Fb"},
+		{"..", "%252E%252E", "%2E%2E"},
+		{".", "%252E", "%2E"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link := segment(tt.name)
+			if link != tt.link {
+				t.Errorf("segment(%q) = %q, want %q", tt.name, link, tt.link)
+			}
+			file := unescapePath(link)
+			if file != tt.file {
+				t.Errorf("unescapePath(%q) = %q, want %q", link, file, tt.file)
+			}
+			if path.Base(file) != file || file == "." || file == ".." {
+				t.Errorf("ファイル名 %q がパスの1要素になっていません", file)
+			}
+		})
+	}
+}
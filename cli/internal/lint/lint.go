@@ -0,0 +1,168 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// LevelOff は無効にしたルールのレベルです。それ以外のレベルは downloader.LevelInfo などの差分の変更と同じ値を使います。
+const LevelOff = 0
+
+// Problem は仕様書がルールに違反している箇所です。
+type Problem struct {
+	Rule  string `json:"rule"`
+	Level int    `json:"level"`
+	// Method と Path は問題のあるオペレーション。仕様書全体の問題では空です。
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// Location はオペレーションの中の問題の場所 (例: parameters.query.limit)
+	Location string `json:"location,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LevelName は問題のレベルを "ERR"、"WARN"、"INFO" のいずれかで返します。
+func (p Problem) LevelName() string {
+	return downloader.Change{Level: p.Level}.LevelName()
+}
+
+// String は Problem を人が読める形式に整形します。
+func (p Problem) String() string {
+	s := p.Message
+	if p.Location != "" {
+		s = p.Location + ": " + s
+	}
+	if p.Method != "" {
+		s = fmt.Sprintf("%s %s: %s", p.Method, p.Path, s)
+	}
+	return fmt.Sprintf("[%s] %s (%s)", p.LevelName(), s, p.Rule)
+}
+
+// Ruleset はルールごとのレベルの上書きと、ルールの設定です。
+//
+//	rules:
+//	  operation-tags: off
+//	  parameter-description: ERR
+//	pagination:
+//	  parameters: [limit, cursor]
+type Ruleset struct {
+	// Rules はルールの ID からレベル (ERR, WARN, INFO, off) への対応です。指定しないルールは既定のレベルを使います。
+	Rules      map[string]string `yaml:"rules"`
+	Pagination Pagination        `yaml:"pagination"`
+
+	levels map[string]int
+}
+
+// Pagination は一覧を返すオペレーションのページングの規約です。
+type Pagination struct {
+	// Parameters は一覧を返す GET オペレーションが持つべきクエリパラメータです。
+	Parameters []string `yaml:"parameters"`
+}
+
+// DefaultRuleset は組み込みのルールを既定のレベルで使うルールセットを返します。
+func DefaultRuleset() *Ruleset {
+	r := &Ruleset{}
+	// 既定値は検証を通るため、エラーは起こらない
+	_ = r.compile()
+	return r
+}
+
+// LoadRuleset はルールセットのファイルを読み込んで検証します。file が空の場合は DefaultRuleset を返します。
+func LoadRuleset(file string) (*Ruleset, error) {
+	if file == "" {
+		return DefaultRuleset(), nil
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("lint のルールファイル '%s' の読み込みに失敗しました: %w", file, err)
+	}
+	r := &Ruleset{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(r); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("lint のルールファイル '%s' の解析に失敗しました: %w", file, err)
+	}
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("lint のルールファイル '%s' が不正です: %w", file, err)
+	}
+	return r, nil
+}
+
+// compile はルールの ID とレベルを検証し、既定値を補います。
+func (r *Ruleset) compile() error {
+	var errs []error
+	r.levels = make(map[string]int, len(builtinRules))
+	for _, rule := range builtinRules {
+		r.levels[rule.ID] = rule.Level
+	}
+	ids := make([]string, 0, len(r.Rules))
+	for id := range r.Rules {
+		ids = append(ids, id)
+	}
+	// エラーの順序が毎回同じになるよう ID の順に検証する
+	sort.Strings(ids)
+	for _, id := range ids {
+		level := r.Rules[id]
+		if _, ok := r.levels[id]; !ok {
+			errs = append(errs, fmt.Errorf("rules: 未知のルールです: %s", id))
+			continue
+		}
+		if strings.EqualFold(level, "off") {
+			r.levels[id] = LevelOff
+			continue
+		}
+		n, err := downloader.ParseLevel(level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rules.%s: %w", id, err))
+			continue
+		}
+		r.levels[id] = n
+	}
+	if len(r.Pagination.Parameters) == 0 {
+		r.Pagination.Parameters = []string{"limit", "offset"}
+	}
+	return errors.Join(errs...)
+}
+
+// Level はルールのレベルを返します。無効にしたルールは LevelOff です。
+func (r *Ruleset) Level(id string) int {
+	return r.levels[id]
+}
+
+// Lint は仕様書を全ての有効なルールで検査し、レベルの高い順に問題を返します。
+func (r *Ruleset) Lint(doc *openapi3.T) []Problem {
+	problems := []Problem{}
+	if doc == nil {
+		return problems
+	}
+	for _, rule := range builtinRules {
+		level := r.Level(rule.ID)
+		if level == LevelOff {
+			continue
+		}
+		for _, p := range rule.check(doc, r) {
+			p.Rule, p.Level = rule.ID, level
+			problems = append(problems, p)
+		}
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Level > problems[j].Level })
+	return problems
+}
+
+// Count は level 以上の問題の数を返します。
+func Count(problems []Problem, level int) int {
+	count := 0
+	for _, p := range problems {
+		if p.Level >= level {
+			count++
+		}
+	}
+	return count
+}
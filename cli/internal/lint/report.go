@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"io"
+	"strings"
+)
+
+// 結果の出力フォーマット
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Formats は Write が対応している出力フォーマットです。
+var Formats = []string{FormatText, FormatJSON}
+
+// Target は検査した1つの仕様書と、見つかった問題です。
+type Target struct {
+	// Name は API 名とバージョン、または仕様の指定
+	Name     string    `json:"name"`
+	Problems []Problem `json:"problems"`
+}
+
+// ValidateFormat は出力フォーマットが対応しているかを検証します。
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応の出力フォーマットです: %s (%s)", format, strings.Join(Formats, ", "))
+}
+
+// Write は検査の結果を format で書き出します。
+func Write(w io.Writer, targets []Target, format string) error {
+	switch format {
+	case FormatText:
+		writeText(w, targets)
+		return nil
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(targets)
+	}
+	return ValidateFormat(format)
+}
+
+func writeText(w io.Writer, targets []Target) {
+	counts := make(map[int]int)
+	for _, target := range targets {
+		fmt.Fprintf(w, "%s: %d件の問題\n", target.Name, len(target.Problems))
+		for _, p := range target.Problems {
+			fmt.Fprintf(w, "  %s\n", p)
+			counts[p.Level]++
+		}
+	}
+	fmt.Fprintf(w, "\nERR: %d件, WARN: %d件, INFO: %d件\n", counts[downloader.LevelErr], counts[downloader.LevelWarn], counts[downloader.LevelInfo])
+}
@@ -0,0 +1,194 @@
+package lint
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rule は組み込みのルールです。
+type Rule struct {
+	ID          string
+	Description string
+	// Level はルールセットで上書きしない場合のレベル
+	Level int
+	check func(doc *openapi3.T, r *Ruleset) []Problem
+}
+
+// camelCase は operationId の命名規則です。
+var camelCase = regexp.MustCompile(`^[a-z][a-zA-Z0-9]*$`)
+
+// builtinRules は組み込みのルールです。問題はこの順に検査します。
+var builtinRules = []Rule{
+	{
+		ID:          "operation-operation-id",
+		Description: "全てのオペレーションに operationId がある",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, _ *Ruleset) []Problem {
+			if op.Operation.OperationID != "" {
+				return nil
+			}
+			return []Problem{operationProblem(op, "", "operationId がありません")}
+		}),
+	},
+	{
+		ID:          "operation-operation-id-camel-case",
+		Description: "operationId が camelCase である",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, _ *Ruleset) []Problem {
+			id := op.Operation.OperationID
+			if id == "" || camelCase.MatchString(id) {
+				return nil
+			}
+			return []Problem{operationProblem(op, "", fmt.Sprintf("operationId '%s' が camelCase ではありません", id))}
+		}),
+	},
+	{
+		ID:          "operation-operation-id-unique",
+		Description: "operationId が仕様書の中で一意である",
+		Level:       downloader.LevelErr,
+		check:       checkUniqueOperationIDs,
+	},
+	{
+		ID:          "operation-tags",
+		Description: "全てのオペレーションにタグがある",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, _ *Ruleset) []Problem {
+			if len(op.Operation.Tags) > 0 {
+				return nil
+			}
+			return []Problem{operationProblem(op, "", "タグがありません")}
+		}),
+	},
+	{
+		ID:          "parameter-description",
+		Description: "全てのパラメータに説明がある",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, _ *Ruleset) []Problem {
+			var problems []Problem
+			for _, ref := range op.Parameters() {
+				if strings.TrimSpace(ref.Value.Description) != "" {
+					continue
+				}
+				problems = append(problems, operationProblem(op, parameterLocation(ref.Value), "パラメータに説明がありません"))
+			}
+			return problems
+		}),
+	},
+	{
+		ID:          "operation-error-responses",
+		Description: "全てのオペレーションにエラーレスポンス (4XX、5XX または default) がある",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, _ *Ruleset) []Problem {
+			if op.Operation.Responses != nil {
+				for code := range op.Operation.Responses.Map() {
+					if code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5") {
+						return nil
+					}
+				}
+			}
+			return []Problem{operationProblem(op, "responses", "エラーレスポンスが定義されていません")}
+		}),
+	},
+	{
+		ID:          "pagination-parameters",
+		Description: "配列を返す GET オペレーションにページングのクエリパラメータがある",
+		Level:       downloader.LevelWarn,
+		check: eachOperation(func(op specutil.Operation, r *Ruleset) []Problem {
+			if op.Method != "GET" || !returnsArray(op.Operation) {
+				return nil
+			}
+			defined := make(map[string]bool)
+			for _, ref := range op.Parameters() {
+				if ref.Value.In == openapi3.ParameterInQuery {
+					defined[ref.Value.Name] = true
+				}
+			}
+			var problems []Problem
+			for _, name := range r.Pagination.Parameters {
+				if !defined[name] {
+					problems = append(problems, operationProblem(op, "parameters.query."+name, "一覧を返すオペレーションにページングのパラメータがありません"))
+				}
+			}
+			return problems
+		}),
+	},
+}
+
+// Rules は組み込みのルールを返します。
+func Rules() []Rule {
+	rules := make([]Rule, len(builtinRules))
+	copy(rules, builtinRules)
+	return rules
+}
+
+// eachOperation はオペレーションごとの検査を仕様書全体の検査にします。
+func eachOperation(check func(op specutil.Operation, r *Ruleset) []Problem) func(doc *openapi3.T, r *Ruleset) []Problem {
+	return func(doc *openapi3.T, r *Ruleset) []Problem {
+		var problems []Problem
+		for _, op := range specutil.Operations(doc) {
+			problems = append(problems, check(op, r)...)
+		}
+		return problems
+	}
+}
+
+func operationProblem(op specutil.Operation, location string, message string) Problem {
+	return Problem{Method: op.Method, Path: op.Path, Location: location, Message: message}
+}
+
+func checkUniqueOperationIDs(doc *openapi3.T, _ *Ruleset) []Problem {
+	keys := make(map[string][]string)
+	var ops []specutil.Operation
+	for _, op := range specutil.Operations(doc) {
+		if id := op.Operation.OperationID; id != "" {
+			keys[id] = append(keys[id], op.Key())
+			ops = append(ops, op)
+		}
+	}
+	var problems []Problem
+	for _, op := range ops {
+		others := keys[op.Operation.OperationID]
+		if len(others) < 2 {
+			continue
+		}
+		var duplicates []string
+		for _, key := range others {
+			if key != op.Key() {
+				duplicates = append(duplicates, key)
+			}
+		}
+		sort.Strings(duplicates)
+		problems = append(problems, operationProblem(op, "", fmt.Sprintf("operationId '%s' が %s と重複しています", op.Operation.OperationID, strings.Join(duplicates, ", "))))
+	}
+	return problems
+}
+
+func parameterLocation(param *openapi3.Parameter) string {
+	return "parameters." + param.In + "." + param.Name
+}
+
+// returnsArray は成功レスポンスの JSON のスキーマが配列かを返します。
+func returnsArray(op *openapi3.Operation) bool {
+	if op.Responses == nil {
+		return false
+	}
+	for code, ref := range op.Responses.Map() {
+		if !strings.HasPrefix(code, "2") || ref == nil || ref.Value == nil {
+			continue
+		}
+		for mimeType, mediaType := range ref.Value.Content {
+			if !specutil.IsJSON(mimeType) || mediaType == nil || mediaType.Schema == nil || mediaType.Schema.Value == nil {
+				continue
+			}
+			if mediaType.Schema.Value.Type.Is(openapi3.TypeArray) {
+				return true
+			}
+		}
+	}
+	return false
+}
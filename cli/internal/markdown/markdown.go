@@ -0,0 +1,512 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/semver"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// untaggedGroup はタグが付いていないエンドポイントの見出しです。
+const untaggedGroup = "その他"
+
+// levelLabels は変更レベルごとの表示名です。
+var levelLabels = map[string]string{
+	"ERR":  "🔴 破壊的変更",
+	"WARN": "🟡 警告",
+	"INFO": "🔵 情報",
+}
+
+// WriteVersion はAPIバージョンをMarkdownに変換し、outputDir/markdown/apiName/version/README.md に書き出します。
+func WriteVersion(doc *parser.APIDocument, outputDir string) (string, error) {
+	dir := filepath.Join(outputDir, "markdown", doc.APIName, doc.Version)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("ディレクトリ '%s' の作成に失敗しました: %w", dir, err)
+	}
+
+	outputPath := filepath.Join(dir, "README.md")
+	return outputPath, os.WriteFile(outputPath, []byte(Render(doc)), 0644)
+}
+
+// Render はAPIバージョンを1つのMarkdown文書に変換します。
+func Render(doc *parser.APIDocument) string {
+	w := &writer{doc: doc.Doc, anchors: specutil.NewAnchors("authentication", "endpoints", "schemas", "changelog")}
+	// リンクする順に関わらず同じアンカー名になるよう、オペレーションとスキーマのアンカーを先に割り当てる
+	for _, op := range specutil.Operations(doc.Doc) {
+		w.operationAnchor(op.Method, op.Path)
+	}
+	for _, name := range specutil.SchemaNames(doc.Doc) {
+		w.schemaAnchor(name)
+	}
+
+	w.overview(doc)
+	w.authentication()
+	w.endpoints()
+	w.schemas()
+	w.changelog(doc.Diffs)
+
+	return w.String()
+}
+
+// writer はMarkdownを組み立てるためのバッファです。
+type writer struct {
+	strings.Builder
+	doc     *openapi3.T
+	anchors *specutil.Anchors
+}
+
+func (w *writer) line(format string, args ...interface{}) {
+	fmt.Fprintf(w, format, args...)
+	w.WriteString("\n")
+}
+
+func (w *writer) anchor(id string) {
+	w.line(`<a id="%s"></a>`, id)
+	w.line("")
+}
+
+func (w *writer) operationAnchor(method string, path string) string {
+	return w.anchors.Get("operation:"+strings.ToUpper(method)+" "+path, specutil.OperationAnchor(method, path))
+}
+
+func (w *writer) schemaAnchor(name string) string {
+	return w.anchors.Get("schema:"+name, specutil.SchemaAnchor(name))
+}
+
+func (w *writer) overview(doc *parser.APIDocument) {
+	title := doc.APIName
+	if doc.Doc.Info != nil && doc.Doc.Info.Title != "" {
+		title = doc.Doc.Info.Title
+	}
+	w.line("# %s %s", title, doc.Version)
+	w.line("")
+	if !doc.Info.Date.IsZero() {
+		w.line("公開日: %s", doc.Info.Date.Format("2006-01-02"))
+		w.line("")
+	}
+	if doc.Doc.Info != nil && doc.Doc.Info.Description != "" {
+		w.line("%s", doc.Doc.Info.Description)
+		w.line("")
+	}
+
+	w.line("- [認証](#authentication)")
+	w.line("- [エンドポイント](#endpoints)")
+	w.line("- [スキーマ](#schemas)")
+	w.line("- [変更履歴](#changelog)")
+	w.line("")
+
+	if len(w.doc.Servers) > 0 {
+		w.line("## サーバー")
+		w.line("")
+		for _, server := range w.doc.Servers {
+			if server == nil {
+				continue
+			}
+			if server.Description != "" {
+				w.line("- `%s` — %s", server.URL, server.Description)
+			} else {
+				w.line("- `%s`", server.URL)
+			}
+		}
+		w.line("")
+	}
+}
+
+func (w *writer) authentication() {
+	w.anchor("authentication")
+	w.line("## 認証")
+	w.line("")
+	if w.doc.Components == nil || len(w.doc.Components.SecuritySchemes) == 0 {
+		w.line("認証は定義されていません。")
+		w.line("")
+		return
+	}
+
+	names := make([]string, 0, len(w.doc.Components.SecuritySchemes))
+	for name := range w.doc.Components.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w.line("| 名前 | タイプ | 詳細 | 説明 |")
+	w.line("| --- | --- | --- | --- |")
+	for _, name := range names {
+		ref := w.doc.Components.SecuritySchemes[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		scheme := ref.Value
+		detail := ""
+		switch scheme.Type {
+		case "http":
+			detail = scheme.Scheme
+			if scheme.BearerFormat != "" {
+				detail += " (" + scheme.BearerFormat + ")"
+			}
+		case "apiKey":
+			detail = fmt.Sprintf("%s: `%s`", scheme.In, scheme.Name)
+		case "openIdConnect":
+			detail = scheme.OpenIdConnectUrl
+		}
+		w.line("| %s | %s | %s | %s |", cell(name), cell(scheme.Type), cell(detail), cell(scheme.Description))
+	}
+	w.line("")
+}
+
+func (w *writer) endpoints() {
+	w.anchor("endpoints")
+	w.line("## エンドポイント")
+	w.line("")
+
+	var groups []string
+	byTag := make(map[string][]specutil.Operation)
+	for _, op := range specutil.Operations(w.doc) {
+		tag := untaggedGroup
+		if len(op.Operation.Tags) > 0 {
+			tag = op.Operation.Tags[0]
+		}
+		if _, ok := byTag[tag]; !ok {
+			groups = append(groups, tag)
+		}
+		byTag[tag] = append(byTag[tag], op)
+	}
+
+	for _, tag := range groups {
+		w.anchor(w.anchors.Get("tag:"+tag, "tag-"+specutil.Slug(tag)))
+		w.line("### %s", tag)
+		w.line("")
+		if t := w.doc.Tags.Get(tag); t != nil && t.Description != "" {
+			w.line("%s", t.Description)
+			w.line("")
+		}
+		for _, op := range byTag[tag] {
+			w.operation(op)
+		}
+	}
+}
+
+func (w *writer) operation(op specutil.Operation) {
+	w.anchor(w.operationAnchor(op.Method, op.Path))
+	w.line("#### `%s %s`", op.Method, op.Path)
+	w.line("")
+	if op.Operation.Deprecated {
+		w.line("> ⚠️ このエンドポイントは非推奨です。")
+		w.line("")
+	}
+	if op.Operation.Summary != "" {
+		w.line("**%s**", op.Operation.Summary)
+		w.line("")
+	}
+	if op.Operation.OperationID != "" {
+		w.line("operationId: `%s`", op.Operation.OperationID)
+		w.line("")
+	}
+	if op.Operation.Description != "" {
+		w.line("%s", op.Operation.Description)
+		w.line("")
+	}
+
+	params := op.Parameters()
+	if len(params) > 0 {
+		w.line("##### パラメータ")
+		w.line("")
+		w.line("| 名前 | 位置 | 型 | 必須 | 説明 |")
+		w.line("| --- | --- | --- | --- | --- |")
+		for _, ref := range params {
+			p := ref.Value
+			w.line("| `%s` | %s | %s | %s | %s |", p.Name, p.In, w.typeLink(p.Schema), yesNo(p.Required), cell(p.Description))
+		}
+		w.line("")
+	}
+
+	if op.Operation.RequestBody != nil && op.Operation.RequestBody.Value != nil {
+		body := op.Operation.RequestBody.Value
+		w.line("##### リクエストボディ")
+		w.line("")
+		if body.Description != "" {
+			w.line("%s", body.Description)
+			w.line("")
+		}
+		w.content(body.Content)
+	}
+
+	if op.Operation.Responses != nil {
+		w.line("##### レスポンス")
+		w.line("")
+		w.line("| ステータス | 説明 | 型 |")
+		w.line("| --- | --- | --- |")
+		codes := make([]string, 0, op.Operation.Responses.Len())
+		for code := range op.Operation.Responses.Map() {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			ref := op.Operation.Responses.Value(code)
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			var types []string
+			for _, mimeType := range sortedMimeTypes(ref.Value.Content) {
+				types = append(types, fmt.Sprintf("%s: %s", mimeType, w.typeLink(ref.Value.Content[mimeType].Schema)))
+			}
+			description := ""
+			if ref.Value.Description != nil {
+				description = *ref.Value.Description
+			}
+			w.line("| %s | %s | %s |", code, cell(description), strings.Join(types, "<br>"))
+		}
+		w.line("")
+
+		for _, code := range codes {
+			ref := op.Operation.Responses.Value(code)
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			for _, mimeType := range sortedMimeTypes(ref.Value.Content) {
+				mediaType := ref.Value.Content[mimeType]
+				if mediaType.Example == nil && len(mediaType.Examples) == 0 {
+					continue
+				}
+				w.line("レスポンス例 (%s, %s):", code, mimeType)
+				w.line("")
+				w.codeBlock(specutil.MediaTypeExample(mediaType))
+			}
+		}
+	}
+}
+
+func (w *writer) content(content openapi3.Content) {
+	for _, mimeType := range sortedMimeTypes(content) {
+		mediaType := content[mimeType]
+		w.line("- `%s`: %s", mimeType, w.typeLink(mediaType.Schema))
+		w.line("")
+		if example := specutil.MediaTypeExample(mediaType); example != nil {
+			w.codeBlock(example)
+		}
+	}
+}
+
+func (w *writer) schemas() {
+	w.anchor("schemas")
+	w.line("## スキーマ")
+	w.line("")
+
+	for _, name := range specutil.SchemaNames(w.doc) {
+		ref := w.doc.Components.Schemas[name]
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		schema := ref.Value
+
+		w.anchor(w.schemaAnchor(name))
+		w.line("### %s", name)
+		w.line("")
+		if schema.Description != "" {
+			w.line("%s", schema.Description)
+			w.line("")
+		}
+
+		if len(schema.Properties) > 0 {
+			required := make(map[string]bool)
+			for _, r := range schema.Required {
+				required[r] = true
+			}
+			props := make([]string, 0, len(schema.Properties))
+			for prop := range schema.Properties {
+				props = append(props, prop)
+			}
+			sort.Strings(props)
+
+			w.line("| プロパティ | 型 | 必須 | 説明 |")
+			w.line("| --- | --- | --- | --- |")
+			for _, prop := range props {
+				propRef := schema.Properties[prop]
+				description := ""
+				if propRef != nil && propRef.Value != nil {
+					description = propRef.Value.Description
+				}
+				w.line("| `%s` | %s | %s | %s |", prop, w.typeLink(propRef), yesNo(required[prop]), cell(description))
+			}
+			w.line("")
+		} else {
+			w.line("型: %s", w.typeLink(ref))
+			w.line("")
+		}
+
+		if len(schema.Enum) > 0 {
+			values := make([]string, 0, len(schema.Enum))
+			for _, v := range schema.Enum {
+				values = append(values, fmt.Sprintf("`%v`", v))
+			}
+			w.line("列挙値: %s", strings.Join(values, ", "))
+			w.line("")
+		}
+
+		if schema.Example != nil {
+			w.line("例:")
+			w.line("")
+			w.codeBlock(schema.Example)
+		}
+	}
+}
+
+// changelog は diff.json の内容を比較先のバージョンごとに出力します。
+// diff.json はこのバージョンを基準に、各バージョンへの変更を保持しています。
+func (w *writer) changelog(diffs downloader.Diffs) {
+	w.anchor("changelog")
+	w.line("## 変更履歴")
+	w.line("")
+	if len(diffs) == 0 {
+		w.line("変更履歴はありません。")
+		w.line("")
+		return
+	}
+
+	versions := make([]string, 0, len(diffs))
+	for version := range diffs {
+		versions = append(versions, version)
+	}
+	semver.Sort(versions)
+
+	for _, version := range versions {
+		w.anchor(w.anchors.Get("changelog:"+version, "changelog-"+specutil.Slug(version)))
+		w.line("### このバージョンから [%s](../%s/README.md) への変更", version, version)
+		w.line("")
+		changes := diffs[version]
+		if len(changes) == 0 {
+			w.line("変更はありません。")
+			w.line("")
+			continue
+		}
+		for _, change := range changes {
+			// オペレーションのない変更 (スキーマの変更など) は対象を書かない
+			if change.Operation == "" {
+				w.line("- %s: %s%s", levelLabels[change.LevelName()], inline(change.Text), acknowledged(change))
+				continue
+			}
+			target := fmt.Sprintf("`%s %s`", change.Operation, change.Path)
+			if w.hasOperation(change.Operation, change.Path) {
+				target = fmt.Sprintf("[%s](#%s)", target, w.operationAnchor(change.Operation, change.Path))
+			}
+			w.line("- %s %s: %s%s", levelLabels[change.LevelName()], target, inline(change.Text), acknowledged(change))
+		}
+		w.line("")
+	}
+}
+
+// RenderChanges は変更の一覧を title を見出しとするMarkdownに変換します。
+func RenderChanges(title string, changes []downloader.Change) string {
+	w := &writer{}
+	w.line("# %s", inline(title))
+	w.line("")
+	if len(changes) == 0 {
+		w.line("変更はありません。")
+		return w.String()
+	}
+	w.line("%d件の変更", len(changes))
+	w.line("")
+	w.line("| レベル | オペレーション | 内容 |")
+	w.line("| --- | --- | --- |")
+	for _, change := range changes {
+		target := change.Section
+		if change.Operation != "" {
+			target = fmt.Sprintf("`%s %s`", change.Operation, change.Path)
+		}
+		text := change.Text
+		if change.Comment != "" {
+			text += "\n" + change.Comment
+		}
+		w.line("| %s | %s | %s%s |", levelLabels[change.LevelName()], target, cell(text), cell(acknowledged(change)))
+	}
+	return w.String()
+}
+
+// acknowledged は承認済みの変更に付ける注記を返します。
+func acknowledged(change downloader.Change) string {
+	switch {
+	case !change.Acknowledged:
+		return ""
+	case change.Reason != "":
+		return fmt.Sprintf(" ✅ 承認済み (%s)", change.Reason)
+	default:
+		return " ✅ 承認済み"
+	}
+}
+
+// hasOperation はこのバージョンにオペレーションが存在するかを返します。
+func (w *writer) hasOperation(method string, path string) bool {
+	if w.doc.Paths == nil || method == "" {
+		return false
+	}
+	pathItem := w.doc.Paths.Value(path)
+	return pathItem != nil && pathItem.GetOperation(strings.ToUpper(method)) != nil
+}
+
+// typeLink はスキーマの型名を返し、参照スキーマであればスキーマ節へのリンクにします。
+func (w *writer) typeLink(ref *openapi3.SchemaRef) string {
+	typeName, schemaName := specutil.SchemaType(ref)
+	if typeName == "" {
+		return ""
+	}
+	if schemaName == "" {
+		return "`" + typeName + "`"
+	}
+	return fmt.Sprintf("[`%s`](#%s)", typeName, w.schemaAnchor(schemaName))
+}
+
+func (w *writer) codeBlock(value interface{}) {
+	lang := "json"
+	var body string
+	if s, ok := value.(string); ok {
+		lang = ""
+		body = s
+	} else {
+		b, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return
+		}
+		body = string(b)
+	}
+	w.line("```%s", lang)
+	w.line("%s", body)
+	w.line("```")
+	w.line("")
+}
+
+func sortedMimeTypes(content openapi3.Content) []string {
+	mimeTypes := make([]string, 0, len(content))
+	for mimeType, mediaType := range content {
+		if mediaType != nil {
+			mimeTypes = append(mimeTypes, mimeType)
+		}
+	}
+	sort.Strings(mimeTypes)
+	return mimeTypes
+}
+
+// cell は表のセルに入れられるように改行とパイプをエスケープします。
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return inline(s)
+}
+
+// inline は改行を含む文字列を1行にまとめます。
+func inline(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "✔"
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/audience"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/export"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/feed"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/htmlsite"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/markdown"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"path/filepath"
+	"sort"
+)
+
+// FormatJSON はNext.jsサイトが参照する api-data.json を出力するフォーマットです。
+const FormatJSON = "json"
+
+// docExporters はAPIバージョンごとにファイルを書き出す出力フォーマットです。
+var docExporters = map[string]func(doc *parser.APIDocument, outputDir string) (string, error){
+	"postman":  export.WritePostman,
+	"insomnia": export.WriteInsomnia,
+	"http":     export.WriteHTTPFile,
+	"markdown": markdown.WriteVersion,
+}
+
+// Options は出力フォーマットに共通する設定です。
+type Options struct {
+	// SiteURL は公開するサイトのURLです。フィードのリンクを絶対URLにするために使います。
+	SiteURL string
+}
+
+// siteExporters は集約済みのサイトデータ全体から書き出す出力フォーマットです。
+var siteExporters = map[string]func(siteData *generator.SiteData, outputDir string, opts Options) (string, error){
+	FormatJSON: func(siteData *generator.SiteData, outputDir string, _ Options) (string, error) {
+		return filepath.Join(outputDir, "data", "api-data.json"), generator.WriteJSON(siteData, outputDir)
+	},
+	"html": func(siteData *generator.SiteData, outputDir string, _ Options) (string, error) {
+		return htmlsite.Write(siteData, outputDir)
+	},
+	"feed": func(siteData *generator.SiteData, outputDir string, opts Options) (string, error) {
+		return feed.Write(siteData, outputDir, opts.SiteURL)
+	},
+}
+
+// Written は書き出したファイルです。
+type Written struct {
+	Format string
+	Path   string
+}
+
+// Formats は対応している出力フォーマットの一覧を返します。
+func Formats() []string {
+	var formats []string
+	for format := range siteExporters {
+		formats = append(formats, format)
+	}
+	for format := range docExporters {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Validate は未対応の出力フォーマットが含まれていないかを確認します。
+func Validate(formats []string) error {
+	for _, format := range formats {
+		_, isDocFormat := docExporters[format]
+		_, isSiteFormat := siteExporters[format]
+		if !isDocFormat && !isSiteFormat {
+			return fmt.Errorf("未対応の出力フォーマットです: %s", format)
+		}
+	}
+	return nil
+}
+
+// Write は指定されたフォーマットでドキュメントを outputDir に書き出します。
+func Write(formats []string, docs []*parser.APIDocument, siteData *generator.SiteData, outputDir string, opts Options) ([]Written, error) {
+	if err := Validate(formats); err != nil {
+		return nil, err
+	}
+
+	var written []Written
+	for _, format := range formats {
+		if exporter, ok := siteExporters[format]; ok {
+			outputPath, err := exporter(siteData, outputDir, opts)
+			if err != nil {
+				return written, err
+			}
+			written = append(written, Written{Format: format, Path: outputPath})
+			continue
+		}
+
+		for _, doc := range docs {
+			outputPath, err := docExporters[format](doc, outputDir)
+			if err != nil {
+				return written, err
+			}
+			written = append(written, Written{Format: format, Path: outputPath})
+		}
+	}
+	return written, nil
+}
+
+// WriteAudiences は docs を対象者ごとに絞り込んで集約し、outputDir/<対象者> に指定されたフォーマットで書き出します。
+func WriteAudiences(formats []string, docs []*parser.APIDocument, audiences []string, outputDir string, opts Options, filter audience.Options) ([]Written, error) {
+	var written []Written
+	for _, name := range audiences {
+		filtered, err := audience.FilterDocs(docs, name, filter)
+		if err != nil {
+			return written, fmt.Errorf("対象者 '%s' 向けの絞り込みに失敗しました: %w", name, err)
+		}
+		siteData, err := generator.Aggregate(filtered)
+		if err != nil {
+			return written, err
+		}
+		w, err := Write(formats, filtered, siteData, filepath.Join(outputDir, name), opts)
+		written = append(written, w...)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
@@ -5,18 +5,25 @@ import (
 	"fmt"
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 type APIDocument struct {
+	Path    string // 仕様ファイルのパス
 	APIName string
 	Version string
 	Info    downloader.Info
 	Diffs   downloader.Diffs
-	Doc     *openapi3.T
+	// StructuralDiffs は structural-diff.json から読み込んだ構造的な差分
+	StructuralDiffs downloader.StructuralDiffs
+	// Lint は lint ステージで見つかった問題
+	Lint []lint.Problem
+	Doc  *openapi3.T
 }
 
 func ParseAPIDocs(rootDir string) ([]*APIDocument, error) {
@@ -30,60 +37,89 @@ func ParseAPIDocs(rootDir string) ([]*APIDocument, error) {
 		if d.IsDir() {
 			return nil
 		}
-		if strings.HasSuffix(path, "info.json") {
-			return nil
-		}
-		if strings.HasSuffix(path, "diff.json") {
-			return nil
-		}
-
-		fmt.Printf("Parsing %s\n", path)
 
-		loader := openapi3.NewLoader()
-		file, err := loader.LoadFromFile(path)
+		document, err := ParseAPIDoc(rootDir, path)
 		if err != nil {
-			return fmt.Errorf("error parsing %s: %w", path, err)
+			return err
 		}
-
-		relPath, _ := filepath.Rel(rootDir, path)
-		parts := strings.Split(filepath.Dir(relPath), string(filepath.Separator))
-		if len(parts) < 2 {
-			fmt.Printf("Skipping %s\n", path)
-			return nil
+		if document != nil {
+			documents = append(documents, document)
 		}
-		apiName := parts[len(parts)-2]
-		apiVerison := parts[len(parts)-1]
 
-		infoPath := filepath.Join(filepath.Dir(path), "info.json")
-		info := downloader.Info{}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", rootDir, err)
+	}
 
-		diffPath := filepath.Join(filepath.Dir(path), "diff.json")
-		diff := downloader.Diffs{}
+	return documents, nil
 
-		readFile, err := os.ReadFile(infoPath)
-		if err == nil {
-			json.Unmarshal(readFile, &info)
-		}
+}
 
-		diffFile, err := os.ReadFile(diffPath)
-		if err == nil {
-			json.Unmarshal(diffFile, &diff)
-		}
+// IsSpecFile は path がOpenAPI仕様ファイルとして解析対象になるかを返します。
+func IsSpecFile(path string) bool {
+	return !strings.HasSuffix(path, "info.json") && !strings.HasSuffix(path, "diff.json")
+}
 
-		documents = append(documents, &APIDocument{
-			APIName: apiName,
-			Version: apiVerison,
-			Doc:     file,
-			Info:    info,
-			Diffs:   diff,
-		})
+// ParseAPIDoc は rootDir/apiName/version/ 以下の1つの仕様ファイルを解析します。
+// 解析対象外のファイルやディレクトリ構成が合わないファイルの場合は nil を返します。
+func ParseAPIDoc(rootDir string, path string) (*APIDocument, error) {
+	if !IsSpecFile(path) {
+		return nil, nil
+	}
 
-		return nil
-	})
+	fmt.Fprintf(os.Stderr, "Parsing %s\n", path)
+
+	loader := openapi3.NewLoader()
+	// デフォルトの読み込み関数はプロセス全体でファイル内容をキャッシュするため、
+	// 同じファイルを読み直したときに変更が反映されるようキャッシュしない関数を使う
+	loader.ReadFromURIFunc = openapi3.ReadFromURIs(openapi3.ReadFromHTTP(http.DefaultClient), openapi3.ReadFromFile)
+	file, err := loader.LoadFromFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing %s: %w", rootDir, err)
+		return nil, fmt.Errorf("error parsing %s: %w", path, err)
 	}
 
-	return documents, nil
+	relPath, _ := filepath.Rel(rootDir, path)
+	parts := strings.Split(filepath.Dir(relPath), string(filepath.Separator))
+	if len(parts) < 2 {
+		fmt.Fprintf(os.Stderr, "Skipping %s\n", path)
+		return nil, nil
+	}
+	apiName := parts[len(parts)-2]
+	apiVerison := parts[len(parts)-1]
+
+	infoPath := filepath.Join(filepath.Dir(path), "info.json")
+	info := downloader.Info{}
+
+	diffPath := filepath.Join(filepath.Dir(path), "diff.json")
+	diff := downloader.Diffs{}
+
+	structuralPath := filepath.Join(filepath.Dir(path), "structural-diff.json")
+	structural := downloader.StructuralDiffs{}
+
+	readFile, err := os.ReadFile(infoPath)
+	if err == nil {
+		json.Unmarshal(readFile, &info)
+	}
+
+	diffFile, err := os.ReadFile(diffPath)
+	if err == nil {
+		json.Unmarshal(diffFile, &diff)
+	}
+
+	structuralFile, err := os.ReadFile(structuralPath)
+	if err == nil {
+		json.Unmarshal(structuralFile, &structural)
+	}
+
+	return &APIDocument{
+		Path:    path,
+		APIName: apiName,
+		Version: apiVerison,
+		Doc:     file,
+		Info:    info,
+		Diffs:   diff,
 
+		StructuralDiffs: structural,
+	}, nil
 }
@@ -0,0 +1,221 @@
+package pipeline
+
+import (
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/audience"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/config"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/diff"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/downloader"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/lint"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/output"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"strings"
+	"time"
+)
+
+// StageResult は1つのステージの実行結果です。
+type StageResult struct {
+	Name     string
+	Duration time.Duration
+	Summary  string
+	Skipped  bool
+}
+
+// Result はパイプライン全体の実行結果です。
+type Result struct {
+	Stages   []StageResult
+	Docs     []*parser.APIDocument
+	SiteData *generator.SiteData
+	Written  []output.Written
+	// VersionMismatches は変更の内容に対してバージョンの上げ方が足りないバージョン
+	VersionMismatches []generator.VersionMismatch
+	// Untranslated は差分メッセージのうち指定したロケールの翻訳が見つからなかったキー
+	Untranslated []string
+	// LintFailures は最新バージョンで lint.failOn 以上の問題
+	LintFailures []generator.LintFailure
+}
+
+// Run はダウンロード、解析、差分計算、lint、生成を順に実行します。
+// 仕様ファイルは一度だけ解析し、差分計算と生成で同じ解析結果を使います。
+// エラーが発生した場合もそれまでのステージの結果を返します。
+func Run(cfg *config.Config) (*Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("設定が不正です: %w", err)
+	}
+
+	result := &Result{}
+
+	var repos []config.Source
+	for _, source := range cfg.Sources {
+		if source.Repo != "" {
+			repos = append(repos, source)
+		}
+	}
+	inputDirs := cfg.InputDirs()
+
+	err := result.stage("download", len(repos) == 0, func() (string, error) {
+		var downloaded []string
+		for _, source := range repos {
+			if err := downloader.Download(source.Repo, cfg.Layout.WorkDir, source.MaxVersionsOrDefault()); err != nil {
+				return "", err
+			}
+			downloaded = append(downloaded, source.Repo)
+		}
+		return fmt.Sprintf("%s を %s にダウンロードしました", strings.Join(downloaded, ", "), cfg.Layout.WorkDir), nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = result.stage("parse", false, func() (string, error) {
+		excluded := 0
+		for _, dir := range inputDirs {
+			docs, err := parser.ParseAPIDocs(dir)
+			if err != nil {
+				return "", err
+			}
+			for _, doc := range docs {
+				if !cfg.APIs.IncludesAPI(doc.APIName) {
+					excluded++
+					continue
+				}
+				result.Docs = append(result.Docs, doc)
+			}
+		}
+		return fmt.Sprintf("%d個のAPIドキュメントを解析しました (除外: %d個)", len(result.Docs), excluded), nil
+	})
+	if err != nil {
+		return result, err
+	}
+	if len(result.Docs) == 0 {
+		return result, fmt.Errorf("対象のOpenAPIファイルが見つかりませんでした: %s", strings.Join(inputDirs, ", "))
+	}
+
+	err = result.stage("diff", !cfg.Diff.Enabled, func() (string, error) {
+		strategy, err := diff.ParseStrategy(cfg.Diff.Strategy)
+		if err != nil {
+			return "", err
+		}
+		localizer, err := diff.NewLocalizer(cfg.Diff.Locale, cfg.Diff.Translations)
+		if err != nil {
+			return "", err
+		}
+		opts := diff.Options{Strategy: strategy, Workers: cfg.Diff.Workers, Localizer: localizer}
+		if cfg.Diff.Rules != "" {
+			if opts.Rules, err = diff.LoadRules(cfg.Diff.Rules); err != nil {
+				return "", err
+			}
+		}
+		if err := diff.ApplyDiffs(result.Docs, opts); err != nil {
+			return "", err
+		}
+		result.Untranslated = localizer.Untranslated()
+		changes := 0
+		for _, doc := range result.Docs {
+			for _, d := range doc.Diffs {
+				changes += len(d)
+			}
+		}
+		return fmt.Sprintf("%d件の変更を検出しました (戦略: %s)", changes, strategy), nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = result.stage("lint", !cfg.Lint.Enabled, func() (string, error) {
+		ruleset, err := lint.LoadRuleset(cfg.Lint.Rules)
+		if err != nil {
+			return "", err
+		}
+		problems := generator.LintDocs(result.Docs, ruleset)
+		if cfg.Lint.FailOn == "" {
+			return fmt.Sprintf("%d件の問題を検出しました", problems), nil
+		}
+		failOn, err := downloader.ParseLevel(cfg.Lint.FailOn)
+		if err != nil {
+			return "", err
+		}
+		result.LintFailures = generator.LintFailuresInLatest(result.Docs, failOn)
+		if len(result.LintFailures) > 0 {
+			return "", fmt.Errorf("最新バージョンに %s 以上の問題が%d件あります", strings.ToUpper(cfg.Lint.FailOn), len(result.LintFailures))
+		}
+		return fmt.Sprintf("%d件の問題を検出しました", problems), nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = result.stage("generate", false, func() (string, error) {
+		siteData, err := generator.Aggregate(result.Docs)
+		if err != nil {
+			return "", err
+		}
+		result.SiteData = siteData
+		result.VersionMismatches = generator.VersionMismatches(siteData)
+
+		invalid := generator.InvalidExamplesInLatest(siteData)
+		if cfg.Output.FailOnInvalidExamples && len(invalid) > 0 {
+			return "", fmt.Errorf("最新バージョンに不正なExampleが%d件あります", len(invalid))
+		}
+
+		opts := output.Options{SiteURL: cfg.Output.SiteURL}
+		var written []output.Written
+		if len(cfg.Output.Audiences) == 0 {
+			written, err = output.Write(cfg.Output.Formats, result.Docs, siteData, cfg.Layout.OutputDir, opts)
+		} else {
+			var filter audience.Options
+			if filter, err = AudienceOptions(cfg, cfg.Lint.Enabled, cfg.Lint.Rules); err != nil {
+				return "", err
+			}
+			written, err = output.WriteAudiences(cfg.Output.Formats, result.Docs, cfg.Output.Audiences, cfg.Layout.OutputDir, opts, filter)
+		}
+		result.Written = written
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%d個のファイルを出力しました (不正なExample: %d件, バージョンの不整合: %d件)", len(written), len(invalid), len(result.VersionMismatches)), nil
+	})
+	return result, err
+}
+
+// AudienceOptions は対象者ごとの差分を設定ファイルの diff の設定で計算し直し、lintEnabled なら lintRules で検査し直す設定を返します。
+// lintRules はフラグと設定ファイルから解決済みのルールファイルのパスです。
+func AudienceOptions(cfg *config.Config, lintEnabled bool, lintRules string) (audience.Options, error) {
+	localizer, err := diff.NewLocalizer(cfg.Diff.Locale, cfg.Diff.Translations)
+	if err != nil {
+		return audience.Options{}, err
+	}
+	opts := audience.Options{Localizer: localizer}
+	if cfg.Diff.Rules != "" {
+		if opts.Rules, err = diff.LoadRules(cfg.Diff.Rules); err != nil {
+			return audience.Options{}, err
+		}
+	}
+	if lintEnabled {
+		if opts.Ruleset, err = lint.LoadRuleset(lintRules); err != nil {
+			return audience.Options{}, err
+		}
+	}
+	return opts, nil
+}
+
+// stage はステージを実行して所要時間と結果を記録します。
+func (r *Result) stage(name string, skip bool, run func() (string, error)) error {
+	if skip {
+		r.Stages = append(r.Stages, StageResult{Name: name, Skipped: true})
+		return nil
+	}
+
+	start := time.Now()
+	summary, err := run()
+	stage := StageResult{Name: name, Duration: time.Since(start), Summary: summary}
+	if err != nil {
+		stage.Summary = err.Error()
+	}
+	r.Stages = append(r.Stages, stage)
+	if err != nil {
+		return fmt.Errorf("%s ステージで失敗しました: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package semver
+
+import "fmt"
+
+// Bump はバージョンの上げ方です。値が大きいほど大きな変更を表します。
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+var bumpNames = []string{"none", "patch", "minor", "major"}
+
+// String は "none", "patch", "minor", "major" のいずれかを返します。
+func (b Bump) String() string {
+	if b < BumpNone || b > BumpMajor {
+		return fmt.Sprintf("Bump(%d)", int(b))
+	}
+	return bumpNames[b]
+}
+
+// MarshalText は Bump を名前で JSON などに書き出します。
+func (b Bump) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText は名前から Bump を読み込みます。
+func (b *Bump) UnmarshalText(text []byte) error {
+	for i, name := range bumpNames {
+		if name == string(text) {
+			*b = Bump(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("未対応のバージョンの上げ方です: %s", text)
+}
+
+// Difference は from から to へのバージョンの上げ方を返します。
+// 同じバージョンや下がっている場合は BumpNone、どちらかがバージョンとして解釈できない場合は ok=false を返します。
+func Difference(from string, to string) (b Bump, ok bool) {
+	vf, okF := Parse(from)
+	vt, okT := Parse(to)
+	if !okF || !okT {
+		return BumpNone, false
+	}
+	switch {
+	case vt.compare(vf) <= 0:
+		return BumpNone, true
+	case vt.Major != vf.Major:
+		return BumpMajor, true
+	case vt.Minor != vf.Minor:
+		return BumpMinor, true
+	default:
+		return BumpPatch, true
+	}
+}
+
+// IsInitialDevelopment は 0.y.z のように、互換性を損なう変更をマイナーバージョンで行えるバージョンかを返します。
+func IsInitialDevelopment(version string) bool {
+	v, ok := Parse(version)
+	return ok && v.Major == 0
+}
@@ -0,0 +1,84 @@
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Range は ">=1.2.0 <2.0.0" のようなバージョンの範囲です。
+// 空白区切りの条件は全て満たす必要があり、"||" で区切った条件はいずれかを満たせば一致します。
+type Range struct {
+	alternatives [][]constraint
+}
+
+type constraint struct {
+	op      string
+	version Version
+}
+
+// ParseRange はバージョンの範囲を解析します。演算子には =, >, >=, <, <= を使え、省略すると = として扱います。
+func ParseRange(s string) (Range, error) {
+	var r Range
+	for _, alternative := range strings.Split(s, "||") {
+		var constraints []constraint
+		for _, field := range strings.Fields(alternative) {
+			op := ""
+			for _, candidate := range []string{">=", "<=", ">", "<", "="} {
+				if strings.HasPrefix(field, candidate) {
+					op = candidate
+					break
+				}
+			}
+			v, ok := Parse(strings.TrimPrefix(field, op))
+			if !ok {
+				return Range{}, fmt.Errorf("不正なバージョンの範囲です: %s", s)
+			}
+			if op == "" {
+				op = "="
+			}
+			constraints = append(constraints, constraint{op: op, version: v})
+		}
+		if len(constraints) == 0 {
+			return Range{}, fmt.Errorf("不正なバージョンの範囲です: %s", s)
+		}
+		r.alternatives = append(r.alternatives, constraints)
+	}
+	return r, nil
+}
+
+// Contains は version が範囲に含まれるかを返します。バージョンとして解釈できない場合は false を返します。
+func (r Range) Contains(version string) bool {
+	v, ok := Parse(version)
+	if !ok {
+		return false
+	}
+	for _, constraints := range r.alternatives {
+		if matchesAll(v, constraints) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAll(v Version, constraints []constraint) bool {
+	for _, c := range constraints {
+		cmp := v.compare(c.version)
+		var ok bool
+		switch c.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package semver
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version は "1.2.3" や "v2.0.0-beta.1" 形式のバージョンです。
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+// Parse はバージョン文字列を解析します。先頭の "v" とビルドメタデータは無視し、
+// 省略されたマイナー・パッチバージョンは0として扱います。数値として解釈できない場合は ok=false を返します。
+func Parse(s string) (v Version, ok bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.Prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Version{}, false
+	}
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Version{}, false
+		}
+		numbers[i] = n
+	}
+	v.Major, v.Minor, v.Patch = numbers[0], numbers[1], numbers[2]
+	return v, true
+}
+
+// compare は2つのバージョンを比較し、a が小さければ負、等しければ0、大きければ正の値を返します。
+func (a Version) compare(b Version) int {
+	if a.Major != b.Major {
+		return a.Major - b.Major
+	}
+	if a.Minor != b.Minor {
+		return a.Minor - b.Minor
+	}
+	if a.Patch != b.Patch {
+		return a.Patch - b.Patch
+	}
+	// プレリリース版は正式版より小さい
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(a.Prerelease, b.Prerelease)
+}
+
+// comparePrerelease は Semantic Versioning 2.0.0 の 11 節に従ってプレリリースの識別子を比較します。
+// "." で区切った識別子を先頭から比べ、数字だけの識別子は数値として、それ以外は ASCII 順で比較します。
+// 数字だけの識別子はそれ以外の識別子より小さく、全ての識別子が等しければ識別子の少ない方が小さくなります。
+func comparePrerelease(a string, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func compareIdentifier(a string, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		if na == nb {
+			return 0
+		}
+		if na < nb {
+			return -1
+		}
+		return 1
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// Compare は2つのバージョン文字列を比較します。
+// バージョンとして解釈できる文字列は数値として比較し、解釈できない文字列は解釈できる文字列より常に前に並べます。
+// 解釈できない文字列どうしと、"v1.0.0" と "1.0.0" のように同じバージョンを表す文字列どうしは文字列として比較します。
+func Compare(a string, b string) int {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+	switch {
+	case okA && okB:
+		if c := va.compare(vb); c != 0 {
+			return c
+		}
+	case okA:
+		return 1
+	case okB:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// Sort はバージョン文字列を古い順に並べ替えます。
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool { return Compare(versions[i], versions[j]) < 0 })
+}
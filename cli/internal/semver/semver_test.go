@@ -0,0 +1,107 @@
+package semver
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "2.0.0", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"v1.2.3", "1.2.3", 1},
+		{"1.2", "1.2.0", -1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1},
+		{"1.0.0-beta", "1.0.0-beta.2", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1},
+		{"latest", "1.0.0", -1},
+		{"1.0.0", "latest", 1},
+		{"draft", "latest", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			got := Compare(tt.a, tt.b)
+			if sign(got) != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if sign(Compare(tt.b, tt.a)) != -tt.want {
+				t.Errorf("Compare(%q, %q) が Compare(%q, %q) と対称ではありません", tt.b, tt.a, tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	// 解釈できないバージョンが混ざっていても並び順が入力の順序によらないこと
+	want := []string{"latest", "main", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0", "1.10.0", "2.0.0"}
+	inputs := [][]string{
+		{"2.0.0", "main", "1.10.0", "1.0.0", "latest", "1.0.0-beta.11", "1.0.0-alpha", "1.0.0-beta.2", "1.0.0-alpha.1"},
+		{"latest", "1.0.0-alpha.1", "2.0.0", "1.0.0-beta.2", "main", "1.0.0", "1.0.0-beta.11", "1.10.0", "1.0.0-alpha"},
+	}
+	for _, input := range inputs {
+		versions := slices.Clone(input)
+		Sort(versions)
+		if !slices.Equal(versions, want) {
+			t.Errorf("Sort(%v) = %v, want %v", input, versions, want)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		rng     string
+		version string
+		want    bool
+	}{
+		{">=1.2.0 <2.0.0", "1.2.0", true},
+		{">=1.2.0 <2.0.0", "1.9.9", true},
+		{">=1.2.0 <2.0.0", "2.0.0", false},
+		{">=1.2.0 <2.0.0", "1.1.0", false},
+		{">1.0.0", "1.0.0", false},
+		{"<=1.0.0", "1.0.0", true},
+		{"1.0.0", "v1.0.0", true},
+		{"=1.0.0", "1.0.1", false},
+		{"<1.0.0 || >=3.0.0", "0.9.0", true},
+		{"<1.0.0 || >=3.0.0", "2.0.0", false},
+		{"<1.0.0 || >=3.0.0", "3.1.0", true},
+		{">=1.0.0", "1.0.0-rc.1", false},
+		{">=1.0.0", "latest", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rng+" "+tt.version, func(t *testing.T) {
+			r, err := ParseRange(tt.rng)
+			if err != nil {
+				t.Fatalf("ParseRange(%q) error = %v", tt.rng, err)
+			}
+			if got := r.Contains(tt.version); got != tt.want {
+				t.Errorf("Contains(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, rng := range []string{"", ">=x", "1.0.0 ||", ">=1.0.0 <"} {
+		if _, err := ParseRange(rng); err == nil {
+			t.Errorf("ParseRange(%q) がエラーを返しませんでした", rng)
+		}
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// broker はServer-Sent Eventsの購読者にイベントを配信します。
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[chan string]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: make(map[chan string]struct{})}
+}
+
+// publish は全ての購読者にイベントを送信します。受信が詰まっている購読者には送信をスキップします。
+func (b *broker) publish(event string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+func (b *broker) subscribe() chan string {
+	ch := make(chan string, 1)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *broker) unsubscribe(ch chan string) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+}
+
+// ServeHTTP はクライアントとの接続を維持し、イベントを text/event-stream で送信します。
+func (b *broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	ch := b.subscribe()
+	defer b.unsubscribe(ch)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-ch:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, event)
+			flusher.Flush()
+		}
+	}
+}
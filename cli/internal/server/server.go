@@ -0,0 +1,276 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/generator"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/htmlsite"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/parser"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout は停止時に処理中のリクエストの完了を待つ時間です。
+const shutdownTimeout = 5 * time.Second
+
+// eventsPath はライブリロード用のイベントストリームのパスです。
+const eventsPath = "/__events"
+
+// reloadScript はHTMLページに挿入するライブリロード用のスクリプトです。
+const reloadScript = `<script>new EventSource("` + eventsPath + `").addEventListener("reload", function () { location.reload(); });</script>`
+
+// Server は入力ディレクトリを監視し、生成したドキュメントをHTTPで配信します。
+type Server struct {
+	inputDirs []string
+	interval  time.Duration
+	broker    *broker
+	// includeAPI は配信の対象にするAPI名かを返します
+	includeAPI func(string) bool
+
+	// docs は仕様ファイルのパスごとの解析結果です。監視ループからのみ更新されます。
+	docs   map[string]*parser.APIDocument
+	states map[string]fileState
+
+	mu       sync.RWMutex
+	jsonData []byte
+	siteDir  string
+}
+
+// New は入力ディレクトリ全体を解析し、配信の準備ができた Server を返します。
+// includeAPI が false を返すAPIは配信しません。
+func New(inputDirs []string, interval time.Duration, includeAPI func(string) bool) (*Server, error) {
+	s := &Server{
+		inputDirs:  inputDirs,
+		interval:   interval,
+		broker:     newBroker(),
+		includeAPI: includeAPI,
+		docs:       make(map[string]*parser.APIDocument),
+	}
+
+	states, err := snapshot(inputDirs...)
+	if err != nil {
+		return nil, fmt.Errorf("入力ディレクトリの走査に失敗しました: %w", err)
+	}
+	s.states = states
+	for p, state := range states {
+		if err := s.parse(p, state.root); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.regenerate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ListenAndServe は監視ループを開始し、addr でHTTPサーバーを起動します。
+// ctx が終了すると監視ループとHTTPサーバーを停止して nil を返します。
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	go s.watch(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle(eventsPath, s.broker)
+	mux.HandleFunc("/data/api-data.json", s.serveJSON)
+	mux.HandleFunc("/", s.servePage)
+
+	// リクエストのコンテキストを ctx から作り、停止時にイベントストリームの接続も終了させる
+	srv := &http.Server{Addr: addr, Handler: mux, BaseContext: func(net.Listener) context.Context { return ctx }}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.ListenAndServe() }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("サーバーの停止に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// watch は一定間隔で入力ディレクトリをポーリングし、変更があれば再生成してリロードイベントを送信します。
+// 再生成に失敗した場合は監視の状態を更新せず、次の確認で再び再生成を試みます。
+func (s *Server) watch(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	lastErr := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		states, err := snapshot(s.inputDirs...)
+		if err != nil {
+			log.Printf("入力ディレクトリの走査に失敗しました: %v", err)
+			continue
+		}
+		changed := changedFiles(s.states, states)
+		if len(changed) == 0 {
+			continue
+		}
+
+		if err := s.update(changed, states); err != nil {
+			// 同じエラーを確認のたびに表示しないよう、内容が変わったときだけ表示する
+			if err.Error() != lastErr {
+				log.Printf("再生成に失敗しました: %v", err)
+				lastErr = err.Error()
+			}
+			continue
+		}
+		fmt.Printf("%d個のファイルの変更を検知し、再生成しました。\n", len(changed))
+		lastErr = ""
+		s.states = states
+		s.broker.publish("reload")
+	}
+}
+
+// update は変更されたファイルに関係する仕様ファイルだけを再解析し、サイトを再生成します。
+// states は変更後の入力ディレクトリの状態です。
+func (s *Server) update(changed []string, states map[string]fileState) error {
+	targets := make(map[string]bool)
+	for _, p := range changed {
+		if parser.IsSpecFile(p) {
+			targets[p] = true
+			continue
+		}
+		// info.json や diff.json が変わった場合は同じディレクトリの仕様ファイルを読み直す
+		for specPath := range s.docs {
+			if filepath.Dir(specPath) == filepath.Dir(p) {
+				targets[specPath] = true
+			}
+		}
+	}
+
+	for p := range targets {
+		state, exists := states[p]
+		if !exists {
+			delete(s.docs, p)
+			continue
+		}
+		if err := s.parse(p, state.root); err != nil {
+			return err
+		}
+	}
+	return s.regenerate()
+}
+
+// parse は入力ディレクトリ root にある1つのファイルを解析して docs に反映します。
+func (s *Server) parse(p string, root string) error {
+	doc, err := parser.ParseAPIDoc(root, p)
+	if err != nil {
+		return err
+	}
+	if doc == nil || !s.includeAPI(doc.APIName) {
+		delete(s.docs, p)
+		return nil
+	}
+	s.docs[p] = doc
+	return nil
+}
+
+// regenerate は解析済みのドキュメントからJSONとHTMLを作り直し、配信対象を差し替えます。
+func (s *Server) regenerate() error {
+	paths := make([]string, 0, len(s.docs))
+	for p := range s.docs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	docs := make([]*parser.APIDocument, 0, len(paths))
+	for _, p := range paths {
+		docs = append(docs, s.docs[p])
+	}
+
+	siteData, err := generator.Aggregate(docs)
+	if err != nil {
+		return err
+	}
+	jsonData, err := json.MarshalIndent(siteData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSONへのマーシャリングに失敗しました: %w", err)
+	}
+
+	siteDir, err := os.MkdirTemp("", "openapi-serve-")
+	if err != nil {
+		return fmt.Errorf("一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	if _, err := htmlsite.Write(siteData, siteDir); err != nil {
+		os.RemoveAll(siteDir)
+		return err
+	}
+
+	s.mu.Lock()
+	oldDir := s.siteDir
+	s.jsonData = jsonData
+	s.siteDir = siteDir
+	s.mu.Unlock()
+
+	if oldDir != "" {
+		os.RemoveAll(oldDir)
+	}
+	return nil
+}
+
+// Close は生成したHTMLの一時ディレクトリを削除します。
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.siteDir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.siteDir)
+	s.siteDir = ""
+	return err
+}
+
+func (s *Server) serveJSON(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	data := s.jsonData
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// servePage は生成したHTMLを配信します。HTMLにはライブリロード用のスクリプトを挿入します。
+func (s *Server) servePage(w http.ResponseWriter, r *http.Request) {
+	// 読み込みが終わるまで再生成による古いディレクトリの削除を待たせる
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	siteDir := s.siteDir
+
+	urlPath := path.Clean("/" + r.URL.Path)
+	if strings.HasSuffix(r.URL.Path, "/") {
+		urlPath = path.Join(urlPath, "index.html")
+	}
+	filePath := filepath.Join(siteDir, "html", filepath.FromSlash(urlPath))
+
+	if !strings.HasSuffix(filePath, ".html") {
+		http.ServeFile(w, r, filePath)
+		return
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	data = bytes.Replace(data, []byte("</body>"), []byte(reloadScript+"\n</body>"), 1)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(data)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// fileState はポーリングで変更を検知するためのファイルの状態です。
+type fileState struct {
+	modTime time.Time
+	size    int64
+	// root はファイルを見つけた入力ディレクトリです
+	root string
+}
+
+// snapshot は rootDirs 以下の全ファイルの状態を取得します。
+func snapshot(rootDirs ...string) (map[string]fileState, error) {
+	states := make(map[string]fileState)
+	for _, rootDir := range rootDirs {
+		err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			states[path] = fileState{modTime: info.ModTime(), size: info.Size(), root: rootDir}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return states, nil
+}
+
+// changedFiles は2つのスナップショットを比較し、追加・変更・削除されたファイルを返します。
+func changedFiles(before map[string]fileState, after map[string]fileState) []string {
+	var changed []string
+	for path, state := range after {
+		if prev, ok := before[path]; !ok || prev != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
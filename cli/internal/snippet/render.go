@@ -0,0 +1,168 @@
+package snippet
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// shellQuote は文字列をシェルのシングルクォートで囲みます。
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func renderCurl(req Request) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	// 例のないパラメータの {name} を curl が URL のグロブとして解釈しないようにする
+	if strings.ContainsAny(req.URL, "{}[]") {
+		b.WriteString(" --globoff")
+	}
+	fmt.Fprintf(&b, " -X %s %s", req.Method, shellQuote(req.URL))
+	for _, h := range req.Headers {
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote(h.Name+": "+h.Value))
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, " \\\n  -d %s", shellQuote(req.Body))
+	}
+	return b.String()
+}
+
+func renderHTTPie(req Request) string {
+	var b strings.Builder
+	if req.Body != "" {
+		fmt.Fprintf(&b, "echo %s | \\\n  ", shellQuote(req.Body))
+	}
+	fmt.Fprintf(&b, "http %s %s", req.Method, shellQuote(req.URL))
+	for _, h := range req.Headers {
+		fmt.Fprintf(&b, " \\\n  %s", shellQuote(h.Name+":"+h.Value))
+	}
+	return b.String()
+}
+
+func renderGo(req Request) string {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if req.Body != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if req.Body != "" {
+		if strings.Contains(req.Body, "`") {
+			fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", strconv.Quote(req.Body))
+		} else {
+			fmt.Fprintf(&b, "\tbody := strings.NewReader(`%s`)\n", req.Body)
+		}
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(req.Method), strconv.Quote(req.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, h := range req.Headers {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", strconv.Quote(h.Name), strconv.Quote(h.Value))
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, err := io.ReadAll(resp.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(resp.Status, string(data))\n}")
+	return b.String()
+}
+
+func renderPython(req Request) string {
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+	fmt.Fprintf(&b, "url = %s\n", strconv.Quote(req.URL))
+
+	args := []string{"url"}
+	if len(req.Headers) > 0 {
+		b.WriteString("headers = {\n")
+		for _, h := range req.Headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", strconv.Quote(h.Name), strconv.Quote(h.Value))
+		}
+		b.WriteString("}\n")
+		args = append(args, "headers=headers")
+	}
+	if req.JSONBody != nil {
+		fmt.Fprintf(&b, "payload = %s\n", pythonLiteral(req.JSONBody, ""))
+		args = append(args, "json=payload")
+	} else if req.Body != "" {
+		fmt.Fprintf(&b, "payload = %s\n", strconv.Quote(req.Body))
+		args = append(args, "data=payload")
+	}
+
+	fmt.Fprintf(&b, "\nresponse = requests.request(%s, %s)\n", strconv.Quote(req.Method), strings.Join(args, ", "))
+	b.WriteString("print(response.status_code, response.text)")
+	return b.String()
+}
+
+// pythonLiteral はJSONの値をPythonのリテラル表記に変換します。
+func pythonLiteral(value interface{}, indent string) string {
+	next := indent + "    "
+	switch v := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, next+pythonLiteral(item, next))
+		}
+		return "[\n" + strings.Join(items, ",\n") + ",\n" + indent + "]"
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, 0, len(v))
+		for _, k := range keys {
+			items = append(items, next+strconv.Quote(k)+": "+pythonLiteral(v[k], next))
+		}
+		return "{\n" + strings.Join(items, ",\n") + ",\n" + indent + "}"
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "None"
+		}
+		return string(b)
+	}
+}
+
+func renderJavaScript(req Request) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n", strconv.Quote(req.URL))
+	fmt.Fprintf(&b, "  method: %s,\n", strconv.Quote(req.Method))
+	if len(req.Headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, h := range req.Headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", strconv.Quote(h.Name), strconv.Quote(h.Value))
+		}
+		b.WriteString("  },\n")
+	}
+	if req.JSONBody != nil {
+		body := strings.ReplaceAll(req.Body, "\n", "\n  ")
+		fmt.Fprintf(&b, "  body: JSON.stringify(%s),\n", body)
+	} else if req.Body != "" {
+		fmt.Fprintf(&b, "  body: %s,\n", strconv.Quote(req.Body))
+	}
+	b.WriteString("});\n")
+	b.WriteString("console.log(response.status, await response.text());")
+	return b.String()
+}
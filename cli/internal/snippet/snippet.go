@@ -0,0 +1,186 @@
+package snippet
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/usbharu/openapi-static-document-generator/cli/internal/specutil"
+	"net/url"
+	"strings"
+)
+
+// Snippet は1つの言語・ツール向けのコードサンプルです。
+type Snippet struct {
+	Lang  string `json:"lang"`
+	Label string `json:"label"`
+	Code  string `json:"code"`
+}
+
+// Header はリクエストヘッダーです。順序を保つためマップではなくスライスで扱います。
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Request はコードサンプルの元となるリクエストの内容です。
+type Request struct {
+	Method   string
+	URL      string // クエリ文字列を含む
+	Headers  []Header
+	MimeType string
+	Body     string
+	JSONBody interface{} // ボディがJSONの場合のデコード済みの値
+}
+
+// renderer は Request を特定の言語のコードに変換します。
+type renderer struct {
+	lang   string
+	label  string
+	render func(req Request) string
+}
+
+var renderers = []renderer{
+	{lang: "curl", label: "cURL", render: renderCurl},
+	{lang: "go", label: "Go", render: renderGo},
+	{lang: "python", label: "Python", render: renderPython},
+	{lang: "javascript", label: "JavaScript", render: renderJavaScript},
+	{lang: "httpie", label: "HTTPie", render: renderHTTPie},
+}
+
+// Generate は仕様書内の全オペレーションのコードサンプルを生成します。
+// 戻り値はパス、小文字のHTTPメソッドの順にネストしたマップです。
+func Generate(doc *openapi3.T) map[string]map[string][]Snippet {
+	snippets := make(map[string]map[string][]Snippet)
+	for _, op := range specutil.Operations(doc) {
+		if snippets[op.Path] == nil {
+			snippets[op.Path] = make(map[string][]Snippet)
+		}
+		snippets[op.Path][strings.ToLower(op.Method)] = ForOperation(doc, op)
+	}
+	return snippets
+}
+
+// ForOperation は1つのオペレーションのコードサンプルを全言語分生成します。
+func ForOperation(doc *openapi3.T, op specutil.Operation) []Snippet {
+	req := BuildRequest(doc, op)
+	snippets := make([]Snippet, 0, len(renderers))
+	for _, r := range renderers {
+		snippets = append(snippets, Snippet{
+			Lang:  r.lang,
+			Label: r.label,
+			Code:  r.render(req),
+		})
+	}
+	return snippets
+}
+
+// BuildRequest はサーバーURL、パラメータ、セキュリティスキーム、リクエストの例示値から Request を組み立てます。
+func BuildRequest(doc *openapi3.T, op specutil.Operation) Request {
+	req := Request{Method: op.Method}
+
+	path := op.Path
+	var query []string
+	var cookies []string
+	for _, ref := range op.Parameters() {
+		param := ref.Value
+		example := specutil.ParameterExample(param)
+		switch param.In {
+		case openapi3.ParameterInPath:
+			if example != nil {
+				path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(FormatValue(example)))
+			}
+		case openapi3.ParameterInQuery:
+			if example == nil && !param.Required {
+				continue
+			}
+			value := "{" + param.Name + "}"
+			if example != nil {
+				value = url.QueryEscape(FormatValue(example))
+			}
+			query = append(query, url.QueryEscape(param.Name)+"="+value)
+		case openapi3.ParameterInHeader:
+			if example == nil && !param.Required {
+				continue
+			}
+			value := "{" + param.Name + "}"
+			if example != nil {
+				value = FormatValue(example)
+			}
+			req.Headers = append(req.Headers, Header{Name: param.Name, Value: value})
+		case openapi3.ParameterInCookie:
+			if example != nil {
+				cookies = append(cookies, param.Name+"="+FormatValue(example))
+			}
+		}
+	}
+
+	for _, credential := range specutil.Credentials(doc, op.Operation) {
+		switch credential.In {
+		case "header":
+			req.Headers = append(req.Headers, Header{Name: credential.Name, Value: credential.Value})
+		case "query":
+			query = append(query, url.QueryEscape(credential.Name)+"="+credential.Value)
+		case "cookie":
+			cookies = append(cookies, credential.Name+"="+credential.Value)
+		}
+	}
+	if len(cookies) > 0 {
+		req.Headers = append(req.Headers, Header{Name: "Cookie", Value: strings.Join(cookies, "; ")})
+	}
+
+	req.URL = specutil.ServerURL(doc, op) + path
+	if len(query) > 0 {
+		req.URL += "?" + strings.Join(query, "&")
+	}
+
+	if mimeType, example, ok := specutil.RequestBody(op.Operation); ok {
+		req.MimeType = mimeType
+		req.Headers = append(req.Headers, Header{Name: "Content-Type", Value: mimeType})
+		req.Body, req.JSONBody = formatBody(mimeType, example)
+	}
+
+	return req
+}
+
+// FormatValue はパラメータの値を文字列に変換します。配列はカンマ区切りに、オブジェクトはJSONにします。
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, FormatValue(item))
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatBody はメディアタイプに合わせてリクエストボディを文字列に変換します。
+func formatBody(mimeType string, example interface{}) (string, interface{}) {
+	if example == nil {
+		return "", nil
+	}
+	if specutil.IsJSON(mimeType) {
+		b, err := json.MarshalIndent(example, "", "  ")
+		if err != nil {
+			return "", nil
+		}
+		return string(b), example
+	}
+	if strings.HasPrefix(mimeType, "application/x-www-form-urlencoded") {
+		if object, ok := example.(map[string]interface{}); ok {
+			values := url.Values{}
+			for k, v := range object {
+				values.Set(k, FormatValue(v))
+			}
+			return values.Encode(), nil
+		}
+	}
+	return FormatValue(example), nil
+}
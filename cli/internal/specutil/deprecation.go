@@ -0,0 +1,107 @@
+package specutil
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 廃止予定日と安定度を表す拡張プロパティ
+const (
+	SunsetExtension         = "x-sunset"
+	StabilityLevelExtension = "x-stability-level"
+)
+
+// Sunset の取得元
+const (
+	SunsetFromExtension = "x-sunset"
+	SunsetFromHeader    = "Sunset"
+)
+
+// sunsetLayouts は廃止予定日として受け付ける日付の形式です。Sunset ヘッダー (RFC 8594) は HTTP-date です。
+var sunsetLayouts = []string{time.RFC3339, "2006-01-02", http.TimeFormat, time.RFC1123, time.RFC1123Z}
+
+// FindOperation は仕様書からメソッドとパスでオペレーションを探します。
+func FindOperation(doc *openapi3.T, method string, path string) (Operation, bool) {
+	if doc == nil || doc.Paths == nil {
+		return Operation{}, false
+	}
+	pathItem := doc.Paths.Value(path)
+	if pathItem == nil {
+		return Operation{}, false
+	}
+	op := pathItem.GetOperation(method)
+	if op == nil {
+		return Operation{}, false
+	}
+	return Operation{Path: path, Method: method, PathItem: pathItem, Operation: op}, true
+}
+
+// StabilityLevel はオペレーションの x-stability-level (draft, alpha, beta, stable など) を返します。
+func (o Operation) StabilityLevel() string {
+	if value, ok := o.Operation.Extensions[StabilityLevelExtension]; ok && value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// Sunset はオペレーションの廃止予定日と取得元を返します。
+// x-sunset を優先し、なければレスポンスの Sunset ヘッダーの例示値・デフォルト値を使います。見つからない場合は空文字を返します。
+func (o Operation) Sunset() (value string, source string) {
+	if value, ok := o.Operation.Extensions[SunsetExtension]; ok && value != nil {
+		return fmt.Sprint(value), SunsetFromExtension
+	}
+	if o.Operation.Responses == nil {
+		return "", ""
+	}
+	codes := make([]string, 0, o.Operation.Responses.Len())
+	for code := range o.Operation.Responses.Map() {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		ref := o.Operation.Responses.Value(code)
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		for name, header := range ref.Value.Headers {
+			if !strings.EqualFold(name, "Sunset") || header == nil || header.Value == nil {
+				continue
+			}
+			if example := headerExample(header.Value); example != "" {
+				return example, SunsetFromHeader
+			}
+		}
+	}
+	return "", ""
+}
+
+// headerExample はヘッダーの example、スキーマの example、default の順に見つかった値を返します。
+func headerExample(header *openapi3.Header) string {
+	if header.Example != nil {
+		return fmt.Sprint(header.Example)
+	}
+	if header.Schema != nil && header.Schema.Value != nil {
+		if header.Schema.Value.Example != nil {
+			return fmt.Sprint(header.Schema.Value.Example)
+		}
+		if header.Schema.Value.Default != nil {
+			return fmt.Sprint(header.Schema.Value.Default)
+		}
+	}
+	return ""
+}
+
+// ParseSunset は廃止予定日を日時として解釈します。RFC 3339、YYYY-MM-DD、HTTP-date の形式に対応します。
+func ParseSunset(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range sunsetLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,176 @@
+package specutil
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strings"
+)
+
+// maxSampleDepth はスキーマからサンプル値を組み立てる際の最大の深さです。
+const maxSampleDepth = 6
+
+// RequestBody はオペレーションのリクエストボディの代表的なメディアタイプと例示値を返します。
+// application/json 系のメディアタイプを優先し、リクエストボディがない場合は ok=false を返します。
+func RequestBody(op *openapi3.Operation) (mimeType string, example interface{}, ok bool) {
+	if op == nil || op.RequestBody == nil || op.RequestBody.Value == nil {
+		return "", nil, false
+	}
+	content := op.RequestBody.Value.Content
+	if len(content) == 0 {
+		return "", nil, false
+	}
+
+	mimeTypes := make([]string, 0, len(content))
+	for mimeType := range content {
+		mimeTypes = append(mimeTypes, mimeType)
+	}
+	sort.SliceStable(mimeTypes, func(i, j int) bool {
+		ji, jj := IsJSON(mimeTypes[i]), IsJSON(mimeTypes[j])
+		if ji != jj {
+			return ji
+		}
+		return mimeTypes[i] < mimeTypes[j]
+	})
+
+	mimeType = mimeTypes[0]
+	return mimeType, MediaTypeExample(content[mimeType]), true
+}
+
+// IsJSON はメディアタイプがJSON形式かどうかを判定します。
+func IsJSON(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "application/json") || strings.HasSuffix(strings.SplitN(mimeType, ";", 2)[0], "+json")
+}
+
+// MediaTypeExample はメディアタイプの例示値を返します。
+// example、examples (キー順で最初のもの)、スキーマの順に探し、見つからなければスキーマからサンプル値を組み立てます。
+func MediaTypeExample(mediaType *openapi3.MediaType) interface{} {
+	if mediaType == nil {
+		return nil
+	}
+	if mediaType.Example != nil {
+		return mediaType.Example
+	}
+	if example := firstExample(mediaType.Examples); example != nil {
+		return example
+	}
+	if mediaType.Schema != nil {
+		return SampleFromSchema(mediaType.Schema.Value)
+	}
+	return nil
+}
+
+// ParameterExample はパラメータの例示値を返します。見つからない場合は nil を返します。
+func ParameterExample(param *openapi3.Parameter) interface{} {
+	if param == nil {
+		return nil
+	}
+	if param.Example != nil {
+		return param.Example
+	}
+	if example := firstExample(param.Examples); example != nil {
+		return example
+	}
+	if param.Schema != nil && param.Schema.Value != nil {
+		if param.Schema.Value.Example != nil {
+			return param.Schema.Value.Example
+		}
+		if param.Schema.Value.Default != nil {
+			return param.Schema.Value.Default
+		}
+		if len(param.Schema.Value.Enum) > 0 {
+			return param.Schema.Value.Enum[0]
+		}
+	}
+	return nil
+}
+
+// firstExample はキー順で最初の値を持つExampleを返します。
+func firstExample(examples openapi3.Examples) interface{} {
+	keys := make([]string, 0, len(examples))
+	for key := range examples {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		exRef := examples[key]
+		if exRef != nil && exRef.Value != nil && exRef.Value.Value != nil {
+			return exRef.Value.Value
+		}
+	}
+	return nil
+}
+
+// SampleFromSchema はスキーマの example/default/enum/型からサンプル値を組み立てます。
+func SampleFromSchema(schema *openapi3.Schema) interface{} {
+	return sampleFromSchema(schema, 0)
+}
+
+func sampleFromSchema(schema *openapi3.Schema, depth int) interface{} {
+	if schema == nil || depth > maxSampleDepth {
+		return nil
+	}
+	if schema.Example != nil {
+		return schema.Example
+	}
+	if schema.Default != nil {
+		return schema.Default
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	if len(schema.AllOf) > 0 {
+		merged := make(map[string]interface{})
+		for _, ref := range schema.AllOf {
+			if value, ok := sampleFromSchema(ref.Value, depth+1).(map[string]interface{}); ok {
+				for k, v := range value {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	if len(schema.OneOf) > 0 {
+		return sampleFromSchema(schema.OneOf[0].Value, depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return sampleFromSchema(schema.AnyOf[0].Value, depth+1)
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeObject) || len(schema.Properties) > 0:
+		object := make(map[string]interface{})
+		for name, prop := range schema.Properties {
+			if prop == nil {
+				continue
+			}
+			object[name] = sampleFromSchema(prop.Value, depth+1)
+		}
+		return object
+	case schema.Type.Is(openapi3.TypeArray):
+		if schema.Items == nil {
+			return []interface{}{}
+		}
+		return []interface{}{sampleFromSchema(schema.Items.Value, depth+1)}
+	case schema.Type.Is(openapi3.TypeString):
+		switch schema.Format {
+		case "date-time":
+			return "2006-01-02T15:04:05Z"
+		case "date":
+			return "2006-01-02"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "email":
+			return "user@example.com"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	case schema.Type.Is(openapi3.TypeInteger):
+		return 0
+	case schema.Type.Is(openapi3.TypeNumber):
+		return 0.0
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return false
+	}
+	return nil
+}
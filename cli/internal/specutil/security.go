@@ -0,0 +1,67 @@
+package specutil
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strings"
+)
+
+// Credential はリクエストに付与する認証情報のプレースホルダーです。
+type Credential struct {
+	SchemeName string
+	Scheme     *openapi3.SecurityScheme
+	In         string // "header", "query", "cookie"
+	Name       string
+	Value      string
+}
+
+// Credentials はオペレーションに適用されるセキュリティ要件のうち最初のものを、
+// リクエストに付与する認証情報のプレースホルダーに変換します。
+func Credentials(doc *openapi3.T, op *openapi3.Operation) []Credential {
+	requirements := doc.Security
+	if op != nil && op.Security != nil {
+		requirements = *op.Security
+	}
+	if len(requirements) == 0 || doc.Components == nil {
+		return nil
+	}
+
+	requirement := requirements[0]
+	names := make([]string, 0, len(requirement))
+	for name := range requirement {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var credentials []Credential
+	for _, name := range names {
+		schemeRef := doc.Components.SecuritySchemes[name]
+		if schemeRef == nil || schemeRef.Value == nil {
+			continue
+		}
+		scheme := schemeRef.Value
+		credential := Credential{SchemeName: name, Scheme: scheme}
+		switch scheme.Type {
+		case "http":
+			credential.In = "header"
+			credential.Name = "Authorization"
+			if strings.EqualFold(scheme.Scheme, "basic") {
+				credential.Value = "Basic YOUR_CREDENTIALS"
+			} else {
+				credential.Value = "Bearer YOUR_TOKEN"
+			}
+		case "apiKey":
+			credential.In = scheme.In
+			credential.Name = scheme.Name
+			credential.Value = "YOUR_API_KEY"
+		case "oauth2", "openIdConnect":
+			credential.In = "header"
+			credential.Name = "Authorization"
+			credential.Value = "Bearer YOUR_ACCESS_TOKEN"
+		default:
+			continue
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
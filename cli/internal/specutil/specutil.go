@@ -0,0 +1,229 @@
+package specutil
+
+import (
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// methodOrder はオペレーションを並べる際のHTTPメソッドの順序です。
+var methodOrder = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "TRACE", "CONNECT"}
+
+// Operation はパスとメソッドの組で特定されるオペレーションです。
+type Operation struct {
+	Path      string
+	Method    string // 大文字のHTTPメソッド
+	PathItem  *openapi3.PathItem
+	Operation *openapi3.Operation
+}
+
+// Key は "GET /pets" 形式のオペレーションのキーを返します。
+func (o Operation) Key() string {
+	return o.Method + " " + o.Path
+}
+
+// Parameters はパスレベルとオペレーションレベルのパラメータをマージして返します。
+// 同じ名前と位置のパラメータはオペレーション側が優先されます。
+func (o Operation) Parameters() openapi3.Parameters {
+	var params openapi3.Parameters
+	seen := make(map[string]bool)
+	for _, ref := range o.Operation.Parameters {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		seen[ref.Value.In+":"+ref.Value.Name] = true
+		params = append(params, ref)
+	}
+	if o.PathItem != nil {
+		for _, ref := range o.PathItem.Parameters {
+			if ref == nil || ref.Value == nil || seen[ref.Value.In+":"+ref.Value.Name] {
+				continue
+			}
+			params = append(params, ref)
+		}
+	}
+	return params
+}
+
+// Operations は仕様書内の全オペレーションをパス、メソッドの順に並べて返します。
+func Operations(doc *openapi3.T) []Operation {
+	var operations []Operation
+	if doc == nil || doc.Paths == nil {
+		return operations
+	}
+
+	paths := doc.Paths.InMatchingOrder()
+	sort.Strings(paths)
+	for _, path := range paths {
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range methodOrder {
+			op := pathItem.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			operations = append(operations, Operation{
+				Path:      path,
+				Method:    method,
+				PathItem:  pathItem,
+				Operation: op,
+			})
+		}
+	}
+	return operations
+}
+
+// SchemaNames は components.schemas のスキーマ名をソートして返します。
+func SchemaNames(doc *openapi3.T) []string {
+	if doc == nil || doc.Components == nil {
+		return nil
+	}
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SchemaNameFromRef は $ref 文字列からスキーマ名を抽出します (例: "#/components/schemas/User" -> "User")
+func SchemaNameFromRef(ref string) string {
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// ServerURL はオペレーションに適用されるサーバーURLを、変数をデフォルト値で置き換えて返します。
+// サーバーが定義されていない場合は空文字を返します。
+func ServerURL(doc *openapi3.T, op Operation) string {
+	servers := doc.Servers
+	if op.PathItem != nil && len(op.PathItem.Servers) > 0 {
+		servers = op.PathItem.Servers
+	}
+	if op.Operation.Servers != nil && len(*op.Operation.Servers) > 0 {
+		servers = *op.Operation.Servers
+	}
+	if len(servers) == 0 || servers[0] == nil {
+		return ""
+	}
+
+	server := servers[0]
+	serverURL := server.URL
+	for name, variable := range server.Variables {
+		if variable == nil {
+			continue
+		}
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", variable.Default)
+	}
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+// Slug はパスやスキーマ名をアンカーやファイル名として使える文字列に変換します。
+// 英数字以外の文字 (日本語など) も文字であれば残し、記号と空白は "-" にまとめます。
+func Slug(s string) string {
+	var b strings.Builder
+	lastDash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+			lastDash = false
+			continue
+		}
+		if !lastDash && b.Len() > 0 {
+			b.WriteRune('-')
+			lastDash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// OperationAnchor はオペレーションのアンカー名を返します (例: "GET /pets/{id}" -> "operation-get-pets-id")
+// "/pets/{id}" と "/pets/id" のように同じ名前になる場合があるため、1つの文書の中では Anchors で重複を避けます。
+func OperationAnchor(method string, path string) string {
+	return "operation-" + Slug(method+" "+path)
+}
+
+// SchemaAnchor はスキーマのアンカー名を返します (例: "User" -> "schema-user")
+func SchemaAnchor(name string) string {
+	return "schema-" + Slug(name)
+}
+
+// Anchors は1つの文書の中で重複しないアンカー名を割り当てます。
+type Anchors struct {
+	used     map[string]bool
+	assigned map[string]string
+}
+
+// NewAnchors は reserved を使用済みとした Anchors を返します。
+func NewAnchors(reserved ...string) *Anchors {
+	a := &Anchors{used: make(map[string]bool), assigned: make(map[string]string)}
+	for _, id := range reserved {
+		a.used[id] = true
+	}
+	return a
+}
+
+// Get は key に割り当てたアンカー名を返します。初めての key には name を割り当て、
+// name が使用済みであれば "-2"、"-3" と連番を付けた名前を割り当てます。
+func (a *Anchors) Get(key string, name string) string {
+	if id, ok := a.assigned[key]; ok {
+		return id
+	}
+	id := name
+	for n := 2; a.used[id]; n++ {
+		id = fmt.Sprintf("%s-%d", name, n)
+	}
+	a.used[id] = true
+	a.assigned[key] = id
+	return id
+}
+
+// SchemaType はスキーマの型を "string(date-time)" や "Pet[]" のような表記で返します。
+// 参照スキーマの場合は ref にスキーマ名を返します。
+func SchemaType(schemaRef *openapi3.SchemaRef) (typeName string, ref string) {
+	if schemaRef == nil {
+		return "", ""
+	}
+	if name := SchemaNameFromRef(schemaRef.Ref); name != "" {
+		return name, name
+	}
+	schema := schemaRef.Value
+	if schema == nil {
+		return "", ""
+	}
+	if schema.Type.Is(openapi3.TypeArray) && schema.Items != nil {
+		itemType, itemRef := SchemaType(schema.Items)
+		return itemType + "[]", itemRef
+	}
+	if len(schema.OneOf) > 0 {
+		return joinSchemaTypes(schema.OneOf, " | "), ""
+	}
+	if len(schema.AnyOf) > 0 {
+		return joinSchemaTypes(schema.AnyOf, " | "), ""
+	}
+	if len(schema.AllOf) > 0 {
+		return joinSchemaTypes(schema.AllOf, " & "), ""
+	}
+	if schema.Type == nil || len(*schema.Type) == 0 {
+		return "any", ""
+	}
+	typeName = strings.Join(schema.Type.Slice(), " | ")
+	if schema.Format != "" {
+		typeName += "(" + schema.Format + ")"
+	}
+	return typeName, ""
+}
+
+func joinSchemaTypes(refs openapi3.SchemaRefs, sep string) string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		name, _ := SchemaType(ref)
+		names = append(names, name)
+	}
+	return strings.Join(names, sep)
+}
@@ -0,0 +1,43 @@
+package specutil
+
+import "testing"
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"GET /pets/{id}", "get-pets-id"},
+		{"User_Profile", "user_profile"},
+		{"その他", "その他"},
+		{"ペット 管理", "ペット-管理"},
+		{"v1.2.0", "v1-2-0"},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.in); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	a := NewAnchors("endpoints")
+	steps := []struct {
+		key  string
+		name string
+		want string
+	}{
+		{"operation:GET /pets/{id}", OperationAnchor("GET", "/pets/{id}"), "operation-get-pets-id"},
+		{"operation:GET /pets/id", OperationAnchor("GET", "/pets/id"), "operation-get-pets-id-2"},
+		// 同じキーには同じアンカーを返す
+		{"operation:GET /pets/{id}", OperationAnchor("GET", "/pets/{id}"), "operation-get-pets-id"},
+		{"tag:endpoints", "endpoints", "endpoints-2"},
+		{"tag:ペット", "tag-" + Slug("ペット"), "tag-ペット"},
+		{"tag:その他", "tag-" + Slug("その他"), "tag-その他"},
+	}
+	for _, s := range steps {
+		if got := a.Get(s.key, s.name); got != s.want {
+			t.Errorf("Get(%q) = %q, want %q", s.key, got, s.want)
+		}
+	}
+}
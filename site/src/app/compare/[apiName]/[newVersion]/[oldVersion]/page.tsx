@@ -1,5 +1,6 @@
 import Link from "next/link";
 import { DiffVersionSelect } from "@/components/diff-version-select";
+import { FieldChanges } from "@/components/field-changes";
 import { SchemaDiff } from "@/components/schema/schema-diff";
 import { Button } from "@/components/ui/button";
 import {
@@ -14,7 +15,9 @@ import {
   getApiData,
   getApiDiff,
   getApiSpec,
+  getApiStructuralDiff,
   getApiVersions,
+  isCumulativeDiff,
 } from "@/lib/api-loader";
 import type { Operation } from "@/lib/types";
 import { encodeToBase64Url } from "@/lib/utils";
@@ -75,6 +78,13 @@ export default async function ComparePage({ params }: ComparePageProps) {
   const p = await params;
 
   const changes = getApiDiff(p.apiName, p.newVersion, p.oldVersion);
+  const structuralDiff = getApiStructuralDiff(
+    p.apiName,
+    p.newVersion,
+    p.oldVersion,
+  );
+
+  const cumulative = isCumulativeDiff(p.apiName, p.newVersion, p.oldVersion);
 
   const otherVersions = getApiVersions(p.apiName).filter(
     value => value !== p.newVersion,
@@ -86,6 +96,11 @@ export default async function ComparePage({ params }: ComparePageProps) {
         {p.apiName}の{p.newVersion}と{p.oldVersion}の比較
       </h1>
       <p>{changes.length}件の変更</p>
+      {cumulative && changes.length > 0 ? (
+        <p className={"text-muted-foreground"}>
+          直接の差分は計算されていないため、間のバージョンごとの変更を累積して表示しています。途中で追加して削除した要素のように、打ち消し合う変更も含みます。
+        </p>
+      ) : null}
       <p>
         <Button variant="link" asChild>
           <Link href={`/docs/${p.apiName}/${p.newVersion}`}>
@@ -153,6 +168,18 @@ export default async function ComparePage({ params }: ComparePageProps) {
                 </Button>
               </CardAction>
               <CardDescription>
+                {value.acknowledged ? (
+                  <span>
+                    承認済み{value.reason ? `: ${value.reason}` : ""}
+                    <br />
+                  </span>
+                ) : null}
+                {value.step ? (
+                  <span>
+                    {value.step.from} → {value.step.to}
+                    <br />
+                  </span>
+                ) : null}
                 Section: {value.section}
                 <br />
                 {value.operation ? (
@@ -160,6 +187,11 @@ export default async function ComparePage({ params }: ComparePageProps) {
                     {value.operation}: {value.path}
                   </span>
                 ) : null}
+                {value.comment ? (
+                  <span className={"block whitespace-pre-wrap"}>
+                    {value.comment}
+                  </span>
+                ) : null}
               </CardDescription>
             </CardHeader>
             <CardContent>
@@ -173,6 +205,7 @@ export default async function ComparePage({ params }: ComparePageProps) {
           </Card>
         );
       })}
+      {structuralDiff ? <FieldChanges diff={structuralDiff} /> : null}
     </div>
   );
 }
@@ -1,5 +1,7 @@
 import { notFound } from "next/navigation";
 import { EndpointGroup } from "@/components/endpoint/endpoint-group";
+import { EndpointHistory } from "@/components/endpoint/endpoint-history";
+import { EndpointSnippets } from "@/components/endpoint/endpoint-snippets";
 import { Badge } from "@/components/ui/badge";
 import {
   Table,
@@ -9,7 +11,12 @@ import {
   TableHeader,
   TableRow,
 } from "@/components/ui/table";
-import { getApiData, getApiSpec } from "@/lib/api-loader";
+import {
+  getApiData,
+  getApiSnippets,
+  getApiSpec,
+  getOperationHistory,
+} from "@/lib/api-loader";
 import {
   decodeFromBase64Url,
   encodeToBase64Url,
@@ -210,6 +217,37 @@ export default async function PathApiDocument({
           endpoints={endpoints}
         ></EndpointGroup>
       </section>
+      <section id={"snippets"} className={"space-y-8"}>
+        {endpoints.map(value => (
+          <EndpointSnippets
+            key={`${value.path}-${value.method}`}
+            method={value.method}
+            path={value.path}
+            snippets={getApiSnippets(
+              p.apiName,
+              p.version,
+              value.path,
+              value.method,
+            )}
+          />
+        ))}
+      </section>
+      <section id={"history"} className={"space-y-8"}>
+        {endpoints.map(value => (
+          <EndpointHistory
+            key={`${value.path}-${value.method}`}
+            apiName={p.apiName}
+            method={value.method}
+            path={value.path}
+            events={getOperationHistory(
+              p.apiName,
+              value.method,
+              value.path,
+              value.operation.operationId,
+            )}
+          />
+        ))}
+      </section>
     </div>
   );
 }
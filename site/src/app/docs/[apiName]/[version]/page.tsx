@@ -1,4 +1,7 @@
 import { notFound } from "next/navigation";
+import { CoverageSummary } from "@/components/coverage-summary";
+import { DeprecationReport } from "@/components/deprecation-report";
+import { LintResults } from "@/components/lint-results";
 import { SchemaViewer } from "@/components/schema/schema-viewer";
 import { Badge } from "@/components/ui/badge";
 import {
@@ -9,7 +12,14 @@ import {
   TableHeader,
   TableRow,
 } from "@/components/ui/table";
-import { getApiData, getApiSpec } from "@/lib/api-loader";
+import {
+  getApiCoverage,
+  getApiData,
+  getApiLint,
+  getApiSpec,
+  getApiVersions,
+  getDeprecations,
+} from "@/lib/api-loader";
 import {
   encodeToBase64Url,
   getMethodBadgeColor,
@@ -41,6 +51,9 @@ export default async function ApiDocumentPage({ params }: PageProps) {
     notFound();
   }
 
+  const versions = getApiVersions(p.apiName);
+  const previousVersion = versions[versions.indexOf(p.version) - 1];
+
   return (
     <div className="space-y-12">
       {/* Overview Section */}
@@ -56,6 +69,25 @@ export default async function ApiDocumentPage({ params }: PageProps) {
         </Badge>
       </section>
 
+      {/* Deprecations Section */}
+      <DeprecationReport
+        apiName={p.apiName}
+        deprecations={getDeprecations(p.apiName)}
+      />
+
+      {/* Coverage Section */}
+      <CoverageSummary
+        coverage={getApiCoverage(p.apiName, p.version)}
+        previous={
+          previousVersion
+            ? getApiCoverage(p.apiName, previousVersion)
+            : undefined
+        }
+      />
+
+      {/* Lint Section */}
+      <LintResults problems={getApiLint(p.apiName, p.version)} />
+
       {/* Endpoints Section */}
       <section id="endpoints" className="space-y-8">
         <h2 className="font-bold text-3xl tracking-tight">Endpoints</h2>
@@ -0,0 +1,82 @@
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import type { Coverage, CoverageMetric } from "@/lib/types";
+
+const metrics: { key: keyof Omit<Coverage, "score">; label: string }[] = [
+  { key: "operationSummaries", label: "summary のあるオペレーション" },
+  { key: "operationDescriptions", label: "description のあるオペレーション" },
+  { key: "taggedOperations", label: "タグのあるオペレーション" },
+  { key: "parameterDescriptions", label: "説明のあるパラメータ" },
+  { key: "schemaExamples", label: "例のあるスキーマ" },
+  { key: "responseExamples", label: "例のあるレスポンス" },
+];
+
+// 対象がない指標は undefined を返す
+const percent = (metric?: CoverageMetric): number | undefined =>
+  metric && metric.total > 0
+    ? (metric.covered * 100) / metric.total
+    : undefined;
+
+const trend = (current?: number, previous?: number): string => {
+  if (current === undefined || previous === undefined) {
+    return "";
+  }
+  const delta = current - previous;
+  if (Math.abs(delta) < 0.05) {
+    return "";
+  }
+  return `${delta > 0 ? "+" : ""}${delta.toFixed(1)}`;
+};
+
+// ドキュメントの充実度を1つ前のバージョンからの増減とともに表示する
+export function CoverageSummary(props: {
+  coverage?: Coverage;
+  previous?: Coverage;
+}) {
+  const { coverage, previous } = props;
+  if (!coverage) {
+    return null;
+  }
+  return (
+    <section id="coverage" className="space-y-8">
+      <h2 className="font-bold text-3xl tracking-tight">
+        ドキュメントの充実度
+      </h2>
+      <p>スコア: {coverage.score.toFixed(1)}</p>
+      <Table>
+        <TableHeader>
+          <TableRow>
+            <TableHead>指標</TableHead>
+            <TableHead>充足率</TableHead>
+            <TableHead>前のバージョンからの増減</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {metrics.map(({ key, label }) => {
+            const metric = coverage[key];
+            const current = percent(metric);
+            return (
+              <TableRow key={key}>
+                <TableCell>{label}</TableCell>
+                <TableCell>
+                  {current === undefined
+                    ? "-"
+                    : `${current.toFixed(1)}% (${metric.covered}/${metric.total})`}
+                </TableCell>
+                <TableCell>
+                  {trend(current, percent(previous?.[key]))}
+                </TableCell>
+              </TableRow>
+            );
+          })}
+        </TableBody>
+      </Table>
+    </section>
+  );
+}
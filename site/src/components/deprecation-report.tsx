@@ -0,0 +1,91 @@
+import { Badge } from "@/components/ui/badge";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import type { Deprecation } from "@/lib/types";
+import { encodeToBase64Url, getMethodBadgeColor } from "@/lib/utils";
+
+// 廃止予定日を過ぎたかはサイトをビルドした時点で判定する
+const isOverdue = (deprecation: Deprecation): boolean =>
+  deprecation.status === "present" &&
+  deprecation.sunsetDate !== undefined &&
+  new Date(deprecation.sunsetDate).getTime() <= Date.now();
+
+const statusLabel = (deprecation: Deprecation): string => {
+  if (deprecation.status === "removed") {
+    return `${deprecation.removedIn} で削除済み`;
+  }
+  if (isOverdue(deprecation)) {
+    return `⚠ 廃止予定日を過ぎても ${deprecation.lastVersion} に残っています`;
+  }
+  return `${deprecation.lastVersion} に残っています`;
+};
+
+type DeprecationReportProps = {
+  apiName: string;
+  deprecations: Deprecation[];
+};
+
+// 非推奨のオペレーションを、非推奨になったバージョンと廃止予定日とともに一覧にする
+export function DeprecationReport({
+  apiName,
+  deprecations,
+}: DeprecationReportProps) {
+  if (deprecations.length === 0) {
+    return null;
+  }
+  return (
+    <section id="deprecations" className="space-y-8">
+      <h2 className="font-bold text-3xl tracking-tight">
+        非推奨のオペレーション
+      </h2>
+      <Table>
+        <TableHeader>
+          <TableRow>
+            <TableHead>オペレーション</TableHead>
+            <TableHead>非推奨化</TableHead>
+            <TableHead>廃止予定日</TableHead>
+            <TableHead>状態</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {deprecations.map(deprecation => (
+            <TableRow key={`${deprecation.method} ${deprecation.path}`}>
+              <TableCell>
+                <Badge
+                  className={`font-bold text-white ${getMethodBadgeColor(deprecation.method)}`}
+                >
+                  {deprecation.method.toUpperCase()}
+                </Badge>{" "}
+                {deprecation.status === "present" ? (
+                  <a
+                    href={`/docs/${apiName}/${deprecation.lastVersion}/endpoints/${encodeToBase64Url(deprecation.path.substring(1))}`}
+                  >
+                    {deprecation.path}
+                  </a>
+                ) : (
+                  deprecation.path
+                )}
+                {deprecation.stabilityLevel ? (
+                  <Badge className={"ml-2"}>{deprecation.stabilityLevel}</Badge>
+                ) : null}
+              </TableCell>
+              <TableCell>{deprecation.deprecatedSince ?? "-"}</TableCell>
+              <TableCell>
+                {deprecation.sunset
+                  ? `${deprecation.sunset} (${deprecation.sunsetSource})`
+                  : "-"}
+              </TableCell>
+              <TableCell>{statusLabel(deprecation)}</TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </section>
+  );
+}
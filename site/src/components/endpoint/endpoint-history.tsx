@@ -0,0 +1,92 @@
+import Link from "next/link";
+import { Badge } from "@/components/ui/badge";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import type { HistoryEvent } from "@/lib/types";
+import { getMethodBadgeColor } from "@/lib/utils";
+
+const eventLabels: { [kind: string]: string } = {
+  added: "追加",
+  changed: "変更",
+  deprecated: "非推奨化",
+  removed: "削除",
+};
+
+type EndpointHistoryProps = {
+  apiName: string;
+  method: string;
+  path: string;
+  events: HistoryEvent[];
+};
+
+// オペレーションが追加、変更、非推奨化、削除されたバージョンを一覧にする
+export function EndpointHistory({
+  apiName,
+  method,
+  path,
+  events,
+}: EndpointHistoryProps) {
+  if (events.length === 0) {
+    return null;
+  }
+  return (
+    <div>
+      <h3 className={"font-bold text-xl"}>
+        <Badge
+          className={`font-bold text-white ${getMethodBadgeColor(method)}`}
+        >
+          {method.toUpperCase()}
+        </Badge>{" "}
+        {path} の変更履歴
+      </h3>
+      <Table>
+        <TableHeader>
+          <TableRow>
+            <TableHead>バージョン</TableHead>
+            <TableHead>日付</TableHead>
+            <TableHead>種類</TableHead>
+            <TableHead>内容</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {events.map((event, index) => (
+            // biome-ignore lint/suspicious/noArrayIndexKey: <explanation>
+            <TableRow key={index}>
+              <TableCell>
+                <Link href={`/docs/${apiName}/${event.version}`}>
+                  {event.version}
+                </Link>
+              </TableCell>
+              <TableCell>{event.date.substring(0, 10)}</TableCell>
+              <TableCell>{eventLabels[event.kind] ?? event.kind}</TableCell>
+              <TableCell className={"whitespace-normal"}>
+                {event.method} {event.path}
+                {event.previousVersion ? (
+                  <Link
+                    className={"ml-2 underline"}
+                    href={`/compare/${apiName}/${event.previousVersion}/${event.version}`}
+                  >
+                    {event.previousVersion}との比較
+                  </Link>
+                ) : null}
+                {event.changes?.map((change, i) => (
+                  // biome-ignore lint/suspicious/noArrayIndexKey: <explanation>
+                  <p key={i}>{change.text}</p>
+                ))}
+                {event.fields?.length ? (
+                  <p>{event.fields.length}項目の変更</p>
+                ) : null}
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </div>
+  );
+}
@@ -0,0 +1,63 @@
+"use client";
+
+import { Prism as SyntaxHighlighter } from "react-syntax-highlighter";
+import { vscDarkPlus } from "react-syntax-highlighter/dist/esm/styles/prism";
+import { Badge } from "@/components/ui/badge";
+import { Tabs, TabsContent, TabsList, TabsTrigger } from "@/components/ui/tabs";
+import type { Snippet } from "@/lib/types";
+import { getMethodBadgeColor } from "@/lib/utils";
+
+// コマンドラインのサンプルはシェルとして色付けする
+const highlightLanguages: { [lang: string]: string } = {
+  curl: "bash",
+  httpie: "bash",
+};
+
+type EndpointSnippetsProps = {
+  method: string;
+  path: string;
+  snippets: Snippet[];
+};
+
+// オペレーションを呼び出すコードサンプルを言語ごとのタブで表示する
+export function EndpointSnippets({
+  method,
+  path,
+  snippets,
+}: EndpointSnippetsProps) {
+  if (snippets.length === 0) {
+    return null;
+  }
+  return (
+    <div>
+      <h3 className={"font-bold text-xl"}>
+        <Badge
+          className={`font-bold text-white ${getMethodBadgeColor(method)}`}
+        >
+          {method.toUpperCase()}
+        </Badge>{" "}
+        {path} のコードサンプル
+      </h3>
+      <Tabs defaultValue={snippets[0].lang} className="w-full">
+        <TabsList>
+          {snippets.map(snippet => (
+            <TabsTrigger key={snippet.lang} value={snippet.lang}>
+              {snippet.label}
+            </TabsTrigger>
+          ))}
+        </TabsList>
+        {snippets.map(snippet => (
+          <TabsContent key={snippet.lang} value={snippet.lang}>
+            <SyntaxHighlighter
+              language={highlightLanguages[snippet.lang] ?? snippet.lang}
+              style={vscDarkPlus}
+              PreTag="div"
+            >
+              {snippet.code}
+            </SyntaxHighlighter>
+          </TabsContent>
+        ))}
+      </Tabs>
+    </div>
+  );
+}
@@ -0,0 +1,121 @@
+import {
+  Card,
+  CardContent,
+  CardHeader,
+  CardTitle,
+} from "@/components/ui/card";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import type { FieldChange, StructuralDiff } from "@/lib/types";
+
+const kindLabels: { [kind: string]: string } = {
+  added: "追加",
+  deleted: "削除",
+  modified: "変更",
+};
+
+const formatValue = (value: any): string => {
+  if (value === undefined) {
+    return "";
+  }
+  if (typeof value === "string") {
+    return value;
+  }
+  return JSON.stringify(value, null, 2);
+};
+
+function FieldChangesCard(props: { title: string; changes: FieldChange[] }) {
+  return (
+    <Card>
+      <CardHeader>
+        <CardTitle>
+          <code>{props.title}</code>
+        </CardTitle>
+      </CardHeader>
+      <CardContent>
+        <Table>
+          <TableHeader>
+            <TableRow>
+              <TableHead>項目</TableHead>
+              <TableHead>種類</TableHead>
+              <TableHead>変更前</TableHead>
+              <TableHead>変更後</TableHead>
+            </TableRow>
+          </TableHeader>
+          <TableBody>
+            {props.changes.map((change, index) => (
+              // biome-ignore lint/suspicious/noArrayIndexKey: <explanation>
+              <TableRow key={index}>
+                <TableCell>
+                  <code>
+                    {change.path.length > 0
+                      ? change.path.join(" › ")
+                      : "(全体)"}
+                  </code>
+                  {change.renamedFrom ? (
+                    <p>
+                      {change.renamedFrom} から名前が変更された可能性があります
+                    </p>
+                  ) : null}
+                  {change.renamedTo ? (
+                    <p>
+                      {change.renamedTo} に名前が変更された可能性があります
+                    </p>
+                  ) : null}
+                </TableCell>
+                <TableCell>{kindLabels[change.kind] ?? change.kind}</TableCell>
+                <TableCell>
+                  <pre className={"whitespace-pre-wrap"}>
+                    {formatValue(change.from)}
+                  </pre>
+                </TableCell>
+                <TableCell>
+                  <pre className={"whitespace-pre-wrap"}>
+                    {formatValue(change.to)}
+                  </pre>
+                </TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      </CardContent>
+    </Card>
+  );
+}
+
+// 説明や例など、互換性の判定に関係しない変更も含めて項目ごとに表示する
+export function FieldChanges(props: { diff: StructuralDiff }) {
+  const operations = Object.entries(props.diff.operations ?? {})
+    .sort(([a], [b]) => a.localeCompare(b))
+    .flatMap(([path, methods]) =>
+      Object.entries(methods)
+        .sort(([a], [b]) => a.localeCompare(b))
+        .map(([method, changes]) => ({ title: `${method} ${path}`, changes })),
+    );
+  const schemas = Object.entries(props.diff.schemas ?? {})
+    .sort(([a], [b]) => a.localeCompare(b))
+    .map(([name, changes]) => ({ title: `スキーマ ${name}`, changes }));
+
+  if (operations.length === 0 && schemas.length === 0) {
+    return null;
+  }
+  return (
+    <div>
+      <h2 className={"font-bold text-2xl"}>項目の変更</h2>
+      <p>説明や例など、互換性の判定に関係しない変更も含みます。</p>
+      {[...operations, ...schemas].map(value => (
+        <FieldChangesCard
+          key={value.title}
+          title={value.title}
+          changes={value.changes}
+        />
+      ))}
+    </div>
+  );
+}
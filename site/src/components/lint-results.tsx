@@ -0,0 +1,65 @@
+import { Badge } from "@/components/ui/badge";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow,
+} from "@/components/ui/table";
+import type { LintProblem } from "@/lib/types";
+import { getMethodBadgeColor } from "@/lib/utils";
+
+const levelNames: { [level: number]: string } = {
+  3: "ERR",
+  2: "WARN",
+  1: "INFO",
+};
+
+// 仕様書のスタイルの検査で見つかった問題をレベルの高い順に一覧にする
+export function LintResults(props: { problems: LintProblem[] }) {
+  if (props.problems.length === 0) {
+    return null;
+  }
+  return (
+    <section id="lint" className="space-y-8">
+      <h2 className="font-bold text-3xl tracking-tight">Lint の結果</h2>
+      <p>{props.problems.length}件の問題</p>
+      <Table>
+        <TableHeader>
+          <TableRow>
+            <TableHead>レベル</TableHead>
+            <TableHead>場所</TableHead>
+            <TableHead>内容</TableHead>
+          </TableRow>
+        </TableHeader>
+        <TableBody>
+          {props.problems.map((problem, index) => (
+            // biome-ignore lint/suspicious/noArrayIndexKey: <explanation>
+            <TableRow key={index}>
+              <TableCell>
+                <Badge>{levelNames[problem.level] ?? "INFO"}</Badge>
+              </TableCell>
+              <TableCell>
+                {problem.method ? (
+                  <>
+                    <Badge
+                      className={`font-bold text-white ${getMethodBadgeColor(problem.method)}`}
+                    >
+                      {problem.method.toUpperCase()}
+                    </Badge>{" "}
+                    <code>{problem.path}</code>
+                  </>
+                ) : null}
+                {problem.location ? <p>{problem.location}</p> : null}
+              </TableCell>
+              <TableCell className={"whitespace-normal"}>
+                {problem.message} ({problem.rule})
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </section>
+  );
+}
@@ -1,6 +1,16 @@
 import fs from "node:fs";
 import path from "node:path";
-import type { Change, OpenAPISpec, SiteData } from "./types";
+import type {
+  Change,
+  Coverage,
+  Deprecation,
+  HistoryEvent,
+  LintProblem,
+  OpenAPISpec,
+  SiteData,
+  Snippet,
+  StructuralDiff,
+} from "./types";
 
 let cache: SiteData | undefined;
 
@@ -39,6 +49,12 @@ type ApiSpecMap = {
       diffs: {
         [version: string]: Change[];
       };
+      structuralDiffs: {
+        [version: string]: StructuralDiff;
+      };
+      lint: LintProblem[];
+      coverage?: Coverage;
+      snippets: { [path: string]: { [method: string]: Snippet[] } };
     };
   };
 };
@@ -62,6 +78,10 @@ export function getApiSpec(
         spec: version.spec,
         examples: version.schemaExamples,
         diffs: version.diffs,
+        structuralDiffs: version.structuralDiffs ?? {},
+        lint: version.lint ?? [],
+        coverage: version.coverage,
+        snippets: version.snippets ?? {},
       });
     });
   });
@@ -92,7 +112,131 @@ export function getApiDiff(
     getApiSpec(apiName, newVersion);
   }
 
-  return apiSpecCache?.[apiName][newVersion]?.diffs[oldVersion] ?? [];
+  const direct = apiSpecCache?.[apiName][newVersion]?.diffs[oldVersion];
+  if (direct) {
+    return direct;
+  }
+  return composeApiDiff(apiName, newVersion, oldVersion);
+}
+
+// 直接の差分が無く、隣り合うバージョンの差分をつなげた累積の差分を返すかを返す
+export function isCumulativeDiff(
+  apiName: string,
+  newVersion: string,
+  oldVersion: string,
+): boolean {
+  if (!apiSpecCache) {
+    getApiSpec(apiName, newVersion);
+  }
+
+  return !apiSpecCache?.[apiName][newVersion]?.diffs[oldVersion];
+}
+
+// 構造的な差分は直接計算した組についてのみ返す
+export function getApiStructuralDiff(
+  apiName: string,
+  newVersion: string,
+  oldVersion: string,
+): StructuralDiff | undefined {
+  if (!apiSpecCache) {
+    getApiSpec(apiName, newVersion);
+  }
+
+  return apiSpecCache?.[apiName][newVersion]?.structuralDiffs[oldVersion];
+}
+
+export function getApiLint(apiName: string, version: string): LintProblem[] {
+  if (!apiSpecCache) {
+    getApiSpec(apiName, version);
+  }
+
+  return apiSpecCache?.[apiName][version]?.lint ?? [];
+}
+
+// オペレーションのコードサンプルを返す。method は小文字の HTTP メソッド
+export function getApiSnippets(
+  apiName: string,
+  version: string,
+  path: string,
+  method: string,
+): Snippet[] {
+  if (!apiSpecCache) {
+    getApiSpec(apiName, version);
+  }
+
+  return apiSpecCache?.[apiName][version]?.snippets[path]?.[method] ?? [];
+}
+
+export function getApiCoverage(
+  apiName: string,
+  version: string,
+): Coverage | undefined {
+  if (!apiSpecCache) {
+    getApiSpec(apiName, version);
+  }
+
+  return apiSpecCache?.[apiName][version]?.coverage;
+}
+
+// 差分戦略によって直接の差分が無い場合、隣り合うバージョンの差分をつなげて返す。
+// 途中で追加して削除した変更のように打ち消し合う変更もそれぞれ残し、変更が入った組を step に記録する。
+// 複数の組で同じ内容の変更は最後の組のものだけを残す
+function composeApiDiff(
+  apiName: string,
+  newVersion: string,
+  oldVersion: string,
+): Change[] {
+  const versions = getApiVersions(apiName);
+  const from = versions.indexOf(newVersion);
+  const to = versions.indexOf(oldVersion);
+  if (from < 0 || to < 0 || from === to) {
+    return [];
+  }
+  const step = from < to ? 1 : -1;
+  const changes = new Map<string, Change>();
+  for (let i = from; i !== to; i += step) {
+    const diff =
+      apiSpecCache?.[apiName][versions[i]]?.diffs[versions[i + step]];
+    if (!diff) {
+      return [];
+    }
+    for (const change of diff) {
+      const key = [change.id, change.operation, change.path, change.text].join(
+        "\u0000",
+      );
+      changes.delete(key);
+      changes.set(key, {
+        ...change,
+        step: { from: versions[i], to: versions[i + step] },
+      });
+    }
+  }
+  return [...changes.values()];
+}
+
+// オペレーションの変更履歴を operationId、メソッドとパスの順に探して返す
+export function getOperationHistory(
+  apiName: string,
+  method: string,
+  path: string,
+  operationId?: string,
+): HistoryEvent[] {
+  const history = getApiData().apis.find(
+    value => value.name === apiName,
+  )?.history;
+  if (!history) {
+    return [];
+  }
+  const index =
+    (operationId ? history.byOperationId[operationId] : undefined) ??
+    history.byPath[`${method.toUpperCase()} ${path}`];
+  return index === undefined ? [] : history.operations[index].events;
+}
+
+export function getDeprecations(apiName: string): Deprecation[] {
+  return (
+    getApiData().apis.find(value => value.name === apiName)?.deprecations ?? []
+  );
 }
 
 export function getApiVersions(apiName: string): string[] {
@@ -5,16 +5,71 @@ export interface SiteData {
 export interface API {
   name: string;
   versions: Version[];
+  history?: History;
+  deprecations?: Deprecation[];
 }
 
+// 非推奨または廃止予定日が宣言されたオペレーション
+export type Deprecation = {
+  method: string;
+  path: string;
+  operationId?: string;
+  deprecatedSince?: string;
+  deprecatedDate?: string;
+  sunset?: string;
+  sunsetSource?: "x-sunset" | "Sunset";
+  sunsetDate?: string;
+  stabilityLevel?: string;
+  status: "present" | "removed";
+  lastVersion: string;
+  removedIn?: string;
+  removedDate?: string;
+};
+
+// 全バージョンにわたるオペレーションごとの変更履歴
+export type History = {
+  operations: OperationHistory[];
+  byPath: { [key: string]: number };
+  byOperationId: { [operationId: string]: number };
+};
+
+export type OperationHistory = {
+  method: string;
+  path: string;
+  operationId?: string;
+  events: HistoryEvent[];
+};
+
+export type HistoryEvent = {
+  version: string;
+  date: string;
+  kind: "added" | "changed" | "deprecated" | "removed";
+  method: string;
+  path: string;
+  previousVersion?: string;
+  changes?: Change[];
+  fields?: FieldChange[];
+};
+
 export interface Version {
   version: string;
   spec: OpenAPISpec;
   info: GitInfo;
   diffs: Diff;
+  structuralDiffs?: { [Version: string]: StructuralDiff };
   schemaExamples: { [path: string]: any };
+  versionCheck?: VersionCheck;
+  lint?: LintProblem[];
+  coverage?: Coverage;
+  snippets: { [path: string]: { [method: string]: Snippet[] } };
 }
 
+export type Snippet = {
+  lang: string;
+  label: string;
+  code: string;
+};
+
 type GitInfo = {
   date: string;
 };
@@ -142,4 +197,63 @@ export type Change = {
   path: string;
   section: string;
   text: string;
+  comment?: string;
+  attributes?: { [key: string]: any };
+  acknowledged?: boolean;
+  reason?: string;
+  originalLevel?: number;
+  renamedFrom?: string;
+  renamedTo?: string;
+  // 隣り合うバージョンの差分をつなげた場合に、変更が入ったバージョンの組 (サイトでのみ設定)
+  step?: { from: string; to: string };
+};
+
+// 説明や例も含む、オペレーションとスキーマごとの項目の変更
+export type StructuralDiff = {
+  operations?: { [path: string]: { [method: string]: FieldChange[] } };
+  schemas?: { [schemaName: string]: FieldChange[] };
+};
+
+export type FieldChange = {
+  path: string[];
+  kind: "added" | "deleted" | "modified";
+  from?: any;
+  to?: any;
+  renamedFrom?: string;
+  renamedTo?: string;
+};
+
+export type VersionCheck = {
+  from: string;
+  to: string;
+  required: "none" | "patch" | "minor" | "major";
+  actual: "none" | "patch" | "minor" | "major";
+  ok: boolean;
+};
+
+// 仕様書のスタイルの検査で見つかった問題
+export type LintProblem = {
+  rule: string;
+  level: number;
+  method?: string;
+  path?: string;
+  location?: string;
+  message: string;
+};
+
+// 対象の数のうち、ドキュメントが揃っているものの数
+export type CoverageMetric = {
+  covered: number;
+  total: number;
+};
+
+// 1つのバージョンの仕様書のドキュメントの充実度
+export type Coverage = {
+  operationSummaries: CoverageMetric;
+  operationDescriptions: CoverageMetric;
+  taggedOperations: CoverageMetric;
+  parameterDescriptions: CoverageMetric;
+  schemaExamples: CoverageMetric;
+  responseExamples: CoverageMetric;
+  score: number;
 };